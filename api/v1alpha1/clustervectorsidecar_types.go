@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterVectorSidecarSpec defines the desired state of ClusterVectorSidecar
+type ClusterVectorSidecarSpec struct {
+	// Enabled controls whether sidecar injection is active for this ClusterVectorSidecar
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled"`
+
+	// NamespaceSelector selects the namespaces whose workloads are matched.
+	// An empty selector selects every namespace.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Selector defines label selectors for matching target workloads in the selected namespaces
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// TargetKinds lists the workload kinds the selector is matched against
+	// +kubebuilder:default={"Deployment"}
+	// +optional
+	TargetKinds []WorkloadKind `json:"targetKinds,omitempty"`
+
+	// Priority decides which ClusterVectorSidecar injects a workload selected by several of them.
+	// The highest priority wins and ties go to the name that sorts first. A namespaced
+	// VectorSidecar selecting the workload always takes precedence, whatever its priority.
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Sidecar defines the Vector sidecar container configuration. A configMapRef must
+	// set the namespace of the ConfigMap; its content is copied into every selected namespace.
+	// +kubebuilder:validation:Required
+	Sidecar SidecarConfig `json:"sidecar"`
+
+	// ConfigReloadPolicy selects how running sidecars pick up changes to the Vector configuration
+	// +kubebuilder:default=RestartPods
+	// +optional
+	ConfigReloadPolicy ConfigReloadPolicy `json:"configReloadPolicy,omitempty"`
+
+	// InitContainers defines optional init containers to inject alongside the sidecar
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Volumes defines additional volumes to mount in the pod
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// UnmatchedGracePeriod is how long a workload in a selected namespace may stop matching the
+	// selector before the sidecar is removed from it
+	// +kubebuilder:default="5m"
+	// +optional
+	UnmatchedGracePeriod *metav1.Duration `json:"unmatchedGracePeriod,omitempty"`
+
+	// ResyncInterval is how often the ClusterVectorSidecar is reconciled when nothing changes
+	// +kubebuilder:default="5m"
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+}
+
+// ClusterTargetStatus reports a workload of one of the selected namespaces
+type ClusterTargetStatus struct {
+	// Namespace is the namespace of the workload
+	Namespace string `json:"namespace"`
+
+	TargetStatus `json:",inline"`
+}
+
+// ClusterVectorSidecarStatus defines the observed state of ClusterVectorSidecar
+type ClusterVectorSidecarStatus struct {
+	// Conditions represent the latest available observations of the ClusterVectorSidecar's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// MatchedNamespaces is the number of namespaces matching the namespace selector
+	// +optional
+	MatchedNamespaces int32 `json:"matchedNamespaces,omitempty"`
+
+	// MatchedWorkloads is the number of workloads matching the selector in the selected namespaces
+	// +optional
+	MatchedWorkloads int32 `json:"matchedWorkloads,omitempty"`
+
+	// InjectedWorkloads is the number of workloads with sidecars injected by this ClusterVectorSidecar
+	// +optional
+	InjectedWorkloads int32 `json:"injectedWorkloads,omitempty"`
+
+	// OverriddenWorkloads is the number of matching workloads left to a namespaced VectorSidecar
+	// +optional
+	OverriddenWorkloads int32 `json:"overriddenWorkloads,omitempty"`
+
+	// UnmatchedTargets lists the injected workloads of selected namespaces that stopped matching
+	// the selector and keep the sidecar until the unmatched grace period elapses
+	// +optional
+	UnmatchedTargets []ClusterTargetStatus `json:"unmatchedTargets,omitempty"`
+
+	// LastUpdateTime is the timestamp of the last status update
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed ClusterVectorSidecar
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=cvs
+//+kubebuilder:printcolumn:name="Enabled",type="boolean",JSONPath=".spec.enabled"
+//+kubebuilder:printcolumn:name="Namespaces",type="integer",JSONPath=".status.matchedNamespaces"
+//+kubebuilder:printcolumn:name="Matched",type="integer",JSONPath=".status.matchedWorkloads"
+//+kubebuilder:printcolumn:name="Injected",type="integer",JSONPath=".status.injectedWorkloads"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterVectorSidecar is the Schema for the clustervectorsidecars API. It injects the
+// Vector sidecar into matching workloads across every namespace its namespace selector selects.
+type ClusterVectorSidecar struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterVectorSidecarSpec   `json:"spec,omitempty"`
+	Status ClusterVectorSidecarStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterVectorSidecarList contains a list of ClusterVectorSidecar
+type ClusterVectorSidecarList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterVectorSidecar `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterVectorSidecar{}, &ClusterVectorSidecarList{})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var clustervectorsidecarlog = logf.Log.WithName("clustervectorsidecar-resource")
+
+// SetupWebhookWithManager registers the ClusterVectorSidecar validating webhook with the Manager
+func (r *ClusterVectorSidecar) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-observability-kontroloop-ai-v1alpha1-clustervectorsidecar,mutating=false,failurePolicy=fail,sideEffects=None,groups=observability.kontroloop.ai,resources=clustervectorsidecars,verbs=create;update,versions=v1alpha1,name=vclustervectorsidecar.observability.kontroloop.ai,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ClusterVectorSidecar{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterVectorSidecar) ValidateCreate() error {
+	clustervectorsidecarlog.Info("validate create", "name", r.Name)
+
+	return r.validateClusterVectorSidecar()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterVectorSidecar) ValidateUpdate(old runtime.Object) error {
+	clustervectorsidecarlog.Info("validate update", "name", r.Name)
+
+	// Let objects that are being deleted through so the finalizer can be removed
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	return r.validateClusterVectorSidecar()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterVectorSidecar) ValidateDelete() error {
+	return nil
+}
+
+// validateClusterVectorSidecar applies the VectorSidecar rules to the fields both kinds share and
+// collects every spec error so they are reported in a single rejection
+func (r *ClusterVectorSidecar) validateClusterVectorSidecar() error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := metav1.LabelSelectorAsSelector(&r.Spec.NamespaceSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), r.Spec.NamespaceSelector, err.Error()))
+	}
+	allErrs = append(allErrs, validateSelector(&r.Spec.Selector, specPath.Child("selector"))...)
+	allErrs = append(allErrs, validateConfigSource(&r.Spec.Sidecar.Config, specPath.Child("sidecar", "config"))...)
+	if ref := r.Spec.Sidecar.Config.ConfigMapRef; ref != nil && ref.Namespace == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("sidecar", "config", "configMapRef", "namespace"),
+			"a ClusterVectorSidecar must set the namespace of the ConfigMap it references"))
+	}
+	allErrs = append(allErrs, validateNames(&r.Spec.Sidecar, r.Spec.InitContainers, r.Spec.Volumes, specPath)...)
+	allErrs = append(allErrs, validateVolumeMounts(&r.Spec.Sidecar, r.Spec.InitContainers, r.Spec.Volumes, specPath)...)
+	allErrs = append(allErrs, validateResources(&r.Spec.Sidecar.Resources, specPath.Child("sidecar", "resources"))...)
+	allErrs = append(allErrs, validateIntervals(r.Spec.UnmatchedGracePeriod, r.Spec.ResyncInterval, specPath)...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("ClusterVectorSidecar").GroupKind(), r.Name, allErrs)
+}
@@ -18,7 +18,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -30,14 +32,54 @@ type VectorSidecarSpec struct {
 	// +kubebuilder:default=true
 	Enabled bool `json:"enabled"`
 
-	// Selector defines label selectors for matching target Deployments
+	// Mode selects whether the VectorSidecar changes the matched workloads or only plans the
+	// changes. In DryRun mode the plan is published in status.plan and no workload is changed.
+	// +kubebuilder:default=Apply
+	// +optional
+	Mode ReconcileMode `json:"mode,omitempty"`
+
+	// Selector defines label selectors for matching target workloads
 	// +kubebuilder:validation:Required
 	Selector metav1.LabelSelector `json:"selector"`
 
+	// TargetKinds lists the workload kinds the selector is matched against
+	// +kubebuilder:default={"Deployment"}
+	// +optional
+	TargetKinds []WorkloadKind `json:"targetKinds,omitempty"`
+
+	// UnmatchedGracePeriod is how long a workload injected by this VectorSidecar may stop matching
+	// the selector or the target kinds before the sidecar is stripped from it
+	// +kubebuilder:default="5m"
+	// +optional
+	UnmatchedGracePeriod *metav1.Duration `json:"unmatchedGracePeriod,omitempty"`
+
+	// ResyncInterval is how often the VectorSidecar is reconciled again after a successful pass,
+	// catching changes the watches do not report
+	// +kubebuilder:default="5m"
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// InjectionStrategy selects whether the sidecar is injected by rewriting the pod template
+	// of matching workloads or by the pod admission webhook when pods are created
+	// +kubebuilder:default=workload
+	// +optional
+	InjectionStrategy InjectionStrategy `json:"injectionStrategy,omitempty"`
+
+	// Priority decides which VectorSidecar injects a workload selected by several of them.
+	// The highest priority wins and ties go to the VectorSidecar whose name sorts first.
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
 	// Sidecar defines the Vector sidecar container configuration
 	// +kubebuilder:validation:Required
 	Sidecar SidecarConfig `json:"sidecar"`
 
+	// ConfigReloadPolicy selects how running sidecars pick up changes to the Vector configuration
+	// +kubebuilder:default=RestartPods
+	// +optional
+	ConfigReloadPolicy ConfigReloadPolicy `json:"configReloadPolicy,omitempty"`
+
 	// InitContainers defines optional init containers to inject alongside the sidecar
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
@@ -45,14 +87,127 @@ type VectorSidecarSpec struct {
 	// Volumes defines additional volumes to mount in the pod
 	// +optional
 	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// RolloutStrategy spreads injection changes across the matched workloads in batches.
+	// When unset, every matched workload is updated in the same pass.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// RollbackPolicy reverts the matched workloads to the last known-good injection when a new
+	// one leaves them unhealthy. When unset, unhealthy workloads are only reported.
+	// +optional
+	RollbackPolicy *RollbackPolicy `json:"rollbackPolicy,omitempty"`
+}
+
+// RolloutStrategy controls how injection changes progress across the matched workloads
+type RolloutStrategy struct {
+	// MaxBatchSize is the number of workloads updated per batch, or a percentage of the
+	// matched workloads rounded up. At least one workload is updated per batch.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxBatchSize *intstr.IntOrString `json:"maxBatchSize,omitempty"`
+
+	// PauseBetweenBatches is how long to wait after a batch finished rolling out before the next starts
+	// +optional
+	PauseBetweenBatches *metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+}
+
+// RollbackPolicy controls when a new injection is reverted
+type RollbackPolicy struct {
+	// FailureBudget is the number of workloads, or the percentage of the matched workloads rounded
+	// down, that may be unhealthy under a new injection before every workload is reverted
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	FailureBudget *intstr.IntOrString `json:"failureBudget,omitempty"`
 }
 
+// WorkloadKind is a kind of workload that can receive the Vector sidecar
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;ReplicaSet;CronJob
+type WorkloadKind string
+
+const (
+	// WorkloadKindDeployment targets apps/v1 Deployments
+	WorkloadKindDeployment WorkloadKind = "Deployment"
+
+	// WorkloadKindStatefulSet targets apps/v1 StatefulSets
+	WorkloadKindStatefulSet WorkloadKind = "StatefulSet"
+
+	// WorkloadKindDaemonSet targets apps/v1 DaemonSets
+	WorkloadKindDaemonSet WorkloadKind = "DaemonSet"
+
+	// WorkloadKindReplicaSet targets apps/v1 ReplicaSets that are not managed by a Deployment
+	WorkloadKindReplicaSet WorkloadKind = "ReplicaSet"
+
+	// WorkloadKindCronJob targets batch/v1 CronJobs through their job template. Bare Jobs
+	// are not a target kind because the pod template of an existing Job is immutable.
+	WorkloadKindCronJob WorkloadKind = "CronJob"
+)
+
+// ReconcileMode selects whether a VectorSidecar changes workloads
+// +kubebuilder:validation:Enum=Apply;DryRun
+type ReconcileMode string
+
+const (
+	// ReconcileModeApply injects, updates and removes the sidecar on the matched workloads
+	ReconcileModeApply ReconcileMode = "Apply"
+
+	// ReconcileModeDryRun publishes the changes Apply would make without changing any workload
+	ReconcileModeDryRun ReconcileMode = "DryRun"
+)
+
+// InjectionStrategy selects how the Vector sidecar reaches the pods of matching workloads
+// +kubebuilder:validation:Enum=workload;pod
+type InjectionStrategy string
+
+const (
+	// InjectionStrategyWorkload rewrites the pod template of matching workloads
+	InjectionStrategyWorkload InjectionStrategy = "workload"
+
+	// InjectionStrategyPod injects into pods at creation time through the mutating webhook.
+	// Matching is done against pod labels and workloads are left untouched.
+	InjectionStrategyPod InjectionStrategy = "pod"
+)
+
+// ConfigReloadPolicy selects how configuration changes reach running sidecars
+// +kubebuilder:validation:Enum=RestartPods;HotReload
+type ConfigReloadPolicy string
+
+const (
+	// ConfigReloadPolicyRestartPods stamps a hash of the configuration onto the pod template,
+	// so a content change rolls the pods of injected workloads
+	ConfigReloadPolicyRestartPods ConfigReloadPolicy = "RestartPods"
+
+	// ConfigReloadPolicyHotReload runs Vector with --watch-config and leaves pods running
+	// while the kubelet syncs the mounted ConfigMap
+	ConfigReloadPolicyHotReload ConfigReloadPolicy = "HotReload"
+)
+
+const (
+	// DefaultSidecarName is the name of the Vector container when sidecar.name is not set
+	DefaultSidecarName = "vector"
+
+	// VectorConfigVolumeName is the volume the operator mounts the Vector configuration from
+	VectorConfigVolumeName = "vector-config"
+
+	// LifecycleVolumeName is the shared emptyDir used to signal Vector that batch work is done
+	LifecycleVolumeName = "vector-lifecycle"
+)
+
 // SidecarConfig defines the Vector sidecar container configuration
 type SidecarConfig struct {
 	// Name of the sidecar container
 	// +kubebuilder:default=vector
 	Name string `json:"name,omitempty"`
 
+	// Mode selects whether Vector runs as a regular container or as a native sidecar,
+	// an init container with restartPolicy Always (Kubernetes 1.29+). Native mode falls
+	// back to container mode on older clusters.
+	// +kubebuilder:default=container
+	// +optional
+	Mode SidecarMode `json:"mode,omitempty"`
+
 	// Image is the Vector container image
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9.\-/:]+:[a-zA-Z0-9.\-_]+$`
@@ -84,13 +239,25 @@ type SidecarConfig struct {
 	Args []string `json:"args,omitempty"`
 }
 
+// SidecarMode selects where the Vector container is placed in the pod
+// +kubebuilder:validation:Enum=container;native
+type SidecarMode string
+
+const (
+	// SidecarModeContainer appends Vector to the pod's containers
+	SidecarModeContainer SidecarMode = "container"
+
+	// SidecarModeNative injects Vector as an init container with restartPolicy Always
+	SidecarModeNative SidecarMode = "native"
+)
+
 // VectorConfig defines the configuration source for Vector
 type VectorConfig struct {
 	// ConfigMapRef references a ConfigMap containing Vector configuration
 	// +optional
 	ConfigMapRef *ConfigMapRef `json:"configMapRef,omitempty"`
 
-	// Inline contains inline Vector configuration (YAML or TOML)
+	// Inline contains inline Vector configuration in YAML
 	// +optional
 	Inline string `json:"inline,omitempty"`
 }
@@ -101,7 +268,13 @@ type ConfigMapRef struct {
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 
-	// Key in the ConfigMap containing the configuration
+	// Namespace of the ConfigMap. Required by ClusterVectorSidecar; a VectorSidecar always
+	// reads the ConfigMap from its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key in the ConfigMap containing the configuration.
+	// The extension (.yaml, .toml or .json) selects the format.
 	// +kubebuilder:default=vector.yaml
 	Key string `json:"key,omitempty"`
 }
@@ -114,11 +287,11 @@ type VectorSidecarStatus struct {
 	// +patchStrategy=merge
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
-	// MatchedDeployments is the number of Deployments matching the selector
+	// MatchedDeployments is the number of workloads matching the selector
 	// +optional
 	MatchedDeployments int32 `json:"matchedDeployments,omitempty"`
 
-	// InjectedDeployments is the number of Deployments with injected sidecars
+	// InjectedDeployments is the number of workloads with injected sidecars
 	// +optional
 	InjectedDeployments int32 `json:"injectedDeployments,omitempty"`
 
@@ -130,11 +303,196 @@ type VectorSidecarStatus struct {
 	// +optional
 	InjectedHash string `json:"injectedHash,omitempty"`
 
+	// Targets reports the injection state of every workload the VectorSidecar injects or removed the sidecar from
+	// +optional
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=name
+	Targets []TargetStatus `json:"targets,omitempty"`
+
+	// OutOfDateTargets is the number of targets whose applied hash differs from the desired hash
+	// +optional
+	OutOfDateTargets int32 `json:"outOfDateTargets,omitempty"`
+
+	// Rollout reports the progress of a batched rollout under spec.rolloutStrategy
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Plan lists the changes the VectorSidecar would make to the matched workloads in DryRun mode
+	// +optional
+	Plan *DryRunPlan `json:"plan,omitempty"`
+
+	// LastKnownGoodHash is the latest injection hash that rolled out to every target healthily
+	// +optional
+	LastKnownGoodHash string `json:"lastKnownGoodHash,omitempty"`
+
+	// FailedHash is the injection hash that was rolled back because it left targets unhealthy.
+	// It is cleared once the spec renders a different injection.
+	// +optional
+	FailedHash string `json:"failedHash,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed VectorSidecar
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// TargetPhase is the injection state of a single workload
+// +kubebuilder:validation:Enum=Pending;Injected;Failed;Unmatched;Removed
+type TargetPhase string
+
+const (
+	// TargetPhasePending means the workload is selected but the desired sidecar has not been applied yet
+	TargetPhasePending TargetPhase = "Pending"
+
+	// TargetPhaseInjected means the workload carries the desired sidecar
+	TargetPhaseInjected TargetPhase = "Injected"
+
+	// TargetPhaseFailed means the last attempt to inject the workload failed
+	TargetPhaseFailed TargetPhase = "Failed"
+
+	// TargetPhaseUnmatched means the workload carries the sidecar but no longer matches, and the
+	// sidecar is stripped once the unmatched grace period elapses
+	TargetPhaseUnmatched TargetPhase = "Unmatched"
+
+	// TargetPhaseRemoved means the sidecar was stripped from the workload
+	TargetPhaseRemoved TargetPhase = "Removed"
+)
+
+// TargetStatus reports the injection state of one workload
+type TargetStatus struct {
+	// Kind is the workload kind
+	Kind WorkloadKind `json:"kind"`
+
+	// Name is the workload name
+	Name string `json:"name"`
+
+	// AppliedHash is the injection hash currently on the workload's pod template
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// DesiredHash is the injection hash the workload should carry
+	// +optional
+	DesiredHash string `json:"desiredHash,omitempty"`
+
+	// Phase is the injection state of the workload
+	Phase TargetPhase `json:"phase"`
+
+	// LastError is the error of the last failed attempt, if any
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastTransitionTime is when the phase last changed
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// DryRunPlan summarizes the changes a VectorSidecar in DryRun mode would make
+type DryRunPlan struct {
+	// Hash is the injection hash the matched workloads would carry
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Injections is the number of workloads the sidecar would be injected into
+	// +optional
+	Injections int32 `json:"injections,omitempty"`
+
+	// Updates is the number of injected workloads that would be updated to the hash
+	// +optional
+	Updates int32 `json:"updates,omitempty"`
+
+	// Removals is the number of workloads the sidecar would be removed from
+	// +optional
+	Removals int32 `json:"removals,omitempty"`
+
+	// PodRestarts is the number of running pods the changes would replace
+	// +optional
+	PodRestarts int32 `json:"podRestarts,omitempty"`
+
+	// CPURequests is the change in CPU requests across the replaced pods
+	// +optional
+	CPURequests resource.Quantity `json:"cpuRequests,omitempty"`
+
+	// MemoryRequests is the change in memory requests across the replaced pods
+	// +optional
+	MemoryRequests resource.Quantity `json:"memoryRequests,omitempty"`
+
+	// Actions lists the change planned for each workload, sorted by kind and name
+	// +optional
+	Actions []PlannedAction `json:"actions,omitempty"`
+}
+
+// PlannedActionType is the change a dry run plans for a workload
+// +kubebuilder:validation:Enum=Inject;Update;Strip
+type PlannedActionType string
+
+const (
+	// PlannedActionInject injects the sidecar into a workload without it
+	PlannedActionInject PlannedActionType = "Inject"
+
+	// PlannedActionUpdate updates an injected workload to the desired hash
+	PlannedActionUpdate PlannedActionType = "Update"
+
+	// PlannedActionStrip removes the sidecar from a workload
+	PlannedActionStrip PlannedActionType = "Strip"
+)
+
+// PlannedAction is the change a dry run plans for one workload
+type PlannedAction struct {
+	// Kind is the workload kind
+	Kind WorkloadKind `json:"kind"`
+
+	// Name is the workload name
+	Name string `json:"name"`
+
+	// Action is the planned change
+	Action PlannedActionType `json:"action"`
+
+	// OldHash is the injection hash on the workload's pod template
+	// +optional
+	OldHash string `json:"oldHash,omitempty"`
+
+	// NewHash is the injection hash the workload would carry
+	// +optional
+	NewHash string `json:"newHash,omitempty"`
+
+	// PodRestarts is the number of running pods the change would replace. ReplicaSets and
+	// CronJobs apply a template change only to the pods they create later.
+	// +optional
+	PodRestarts int32 `json:"podRestarts,omitempty"`
+
+	// CPURequests is the change in CPU requests across the replaced pods
+	// +optional
+	CPURequests resource.Quantity `json:"cpuRequests,omitempty"`
+
+	// MemoryRequests is the change in memory requests across the replaced pods
+	// +optional
+	MemoryRequests resource.Quantity `json:"memoryRequests,omitempty"`
+}
+
+// RolloutStatus records the progress of a batched rollout, so it resumes where it stopped
+// after an operator restart
+type RolloutStatus struct {
+	// Hash is the injection hash being rolled out
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// UpdatedTargets is the number of targets carrying the hash whose rollout completed
+	// +optional
+	UpdatedTargets int32 `json:"updatedTargets,omitempty"`
+
+	// TotalTargets is the number of targets the hash is rolled out to
+	// +optional
+	TotalTargets int32 `json:"totalTargets,omitempty"`
+
+	// CurrentBatch lists the workloads of the batch in progress as Kind/name
+	// +optional
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+
+	// LastBatchCompletionTime is when the last batch finished rolling out
+	// +optional
+	LastBatchCompletionTime *metav1.Time `json:"lastBatchCompletionTime,omitempty"`
+}
+
 // Condition types for VectorSidecar
 const (
 	// ConditionTypeReady indicates the VectorSidecar is ready and injecting sidecars
@@ -145,6 +503,21 @@ const (
 
 	// ConditionTypeConfigValid indicates the Vector configuration is valid
 	ConditionTypeConfigValid string = "ConfigValid"
+
+	// ConditionTypeInlineConfigReady indicates the ConfigMap rendered from inline configuration is in sync
+	ConditionTypeInlineConfigReady string = "InlineConfigReady"
+
+	// ConditionTypeNativeSidecarSupported indicates whether native sidecar mode is in effect or fell back to container mode
+	ConditionTypeNativeSidecarSupported string = "NativeSidecarSupported"
+
+	// ConditionTypeConflict indicates that selected workloads are injected by another resource with higher precedence
+	ConditionTypeConflict string = "Conflict"
+
+	// ConditionTypeProgressing indicates a batched rollout is updating the matched workloads
+	ConditionTypeProgressing string = "Progressing"
+
+	// ConditionTypeDegraded indicates targets are unhealthy under the injection or it was rolled back
+	ConditionTypeDegraded string = "Degraded"
 )
 
 //+kubebuilder:object:root=true
@@ -153,6 +526,7 @@ const (
 //+kubebuilder:printcolumn:name="Enabled",type="boolean",JSONPath=".spec.enabled"
 //+kubebuilder:printcolumn:name="Matched",type="integer",JSONPath=".status.matchedDeployments"
 //+kubebuilder:printcolumn:name="Injected",type="integer",JSONPath=".status.injectedDeployments"
+//+kubebuilder:printcolumn:name="Out-Of-Date",type="integer",JSONPath=".status.outOfDateTargets"
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
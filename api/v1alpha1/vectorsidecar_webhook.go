@@ -0,0 +1,313 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var vectorsidecarlog = logf.Log.WithName("vectorsidecar-resource")
+
+// reservedVolumeNames are the volume names the operator adds to injected pods
+var reservedVolumeNames = map[string]bool{
+	VectorConfigVolumeName: true,
+	LifecycleVolumeName:    true,
+}
+
+// SetupWebhookWithManager registers the VectorSidecar validating webhook with the Manager
+func (r *VectorSidecar) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-observability-kontroloop-ai-v1alpha1-vectorsidecar,mutating=false,failurePolicy=fail,sideEffects=None,groups=observability.kontroloop.ai,resources=vectorsidecars,verbs=create;update,versions=v1alpha1,name=vvectorsidecar.observability.kontroloop.ai,admissionReviewVersions=v1
+
+var _ webhook.Validator = &VectorSidecar{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *VectorSidecar) ValidateCreate() error {
+	vectorsidecarlog.Info("validate create", "name", r.Name)
+
+	return r.validateVectorSidecar()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *VectorSidecar) ValidateUpdate(old runtime.Object) error {
+	vectorsidecarlog.Info("validate update", "name", r.Name)
+
+	// Let objects that are being deleted through so the finalizer can be removed
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	return r.validateVectorSidecar()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *VectorSidecar) ValidateDelete() error {
+	return nil
+}
+
+// validateVectorSidecar collects every spec error so they are reported in a single rejection
+func (r *VectorSidecar) validateVectorSidecar() error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateSelector(&r.Spec.Selector, specPath.Child("selector"))...)
+	allErrs = append(allErrs, validateConfigSource(&r.Spec.Sidecar.Config, specPath.Child("sidecar", "config"))...)
+	allErrs = append(allErrs, r.validateConfigMapNamespace(specPath)...)
+	allErrs = append(allErrs, validateNames(&r.Spec.Sidecar, r.Spec.InitContainers, r.Spec.Volumes, specPath)...)
+	allErrs = append(allErrs, validateVolumeMounts(&r.Spec.Sidecar, r.Spec.InitContainers, r.Spec.Volumes, specPath)...)
+	allErrs = append(allErrs, validateResources(&r.Spec.Sidecar.Resources, specPath.Child("sidecar", "resources"))...)
+	allErrs = append(allErrs, validateIntervals(r.Spec.UnmatchedGracePeriod, r.Spec.ResyncInterval, specPath)...)
+	allErrs = append(allErrs, validateRolloutStrategy(r.Spec.RolloutStrategy, specPath.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateRollbackPolicy(r.Spec.RollbackPolicy, specPath.Child("rollbackPolicy"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("VectorSidecar").GroupKind(), r.Name, allErrs)
+}
+
+// validateSelector rejects malformed selectors and empty ones, which would match every workload in the namespace
+func validateSelector(selector *metav1.LabelSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "selector must set matchLabels or matchExpressions; an empty selector matches every workload"))
+		return allErrs
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, selector, err.Error()))
+	}
+
+	return allErrs
+}
+
+// validateConfigSource requires exactly one of configMapRef and inline
+func validateConfigSource(config *VectorConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch {
+	case config.ConfigMapRef != nil && config.Inline != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("inline"), "configMapRef and inline are mutually exclusive"))
+	case config.ConfigMapRef == nil && config.Inline == "":
+		allErrs = append(allErrs, field.Required(fldPath, "either configMapRef or inline must be specified"))
+	case config.ConfigMapRef != nil && config.ConfigMapRef.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("configMapRef", "name"), ""))
+	}
+
+	return allErrs
+}
+
+// validateConfigMapNamespace rejects references to ConfigMaps outside the VectorSidecar's
+// namespace, which pods in that namespace could not mount
+func (r *VectorSidecar) validateConfigMapNamespace(specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ref := r.Spec.Sidecar.Config.ConfigMapRef
+	if ref != nil && ref.Namespace != "" && ref.Namespace != r.Namespace {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("sidecar", "config", "configMapRef", "namespace"),
+			ref.Namespace, "a VectorSidecar can only reference ConfigMaps in its own namespace"))
+	}
+
+	return allErrs
+}
+
+// validateNames checks that the sidecar and init container names are valid and unique among the
+// containers the operator injects, and that volume names are unique and leave the volumes the
+// operator adds alone. Clashes with the containers of a workload are reported when it is injected.
+func validateNames(sidecar *SidecarConfig, initContainers []corev1.Container, volumes []corev1.Volume, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	sidecarName := sidecar.Name
+	if sidecarName == "" {
+		sidecarName = DefaultSidecarName
+	}
+	namePath := specPath.Child("sidecar", "name")
+	for _, msg := range validation.IsDNS1123Label(sidecarName) {
+		allErrs = append(allErrs, field.Invalid(namePath, sidecarName, msg))
+	}
+
+	containerNames := map[string]bool{sidecarName: true}
+	for i, initContainer := range initContainers {
+		fldPath := specPath.Child("initContainers").Index(i).Child("name")
+		switch {
+		case initContainer.Name == "":
+			allErrs = append(allErrs, field.Required(fldPath, ""))
+		case initContainer.Name == sidecarName:
+			allErrs = append(allErrs, field.Invalid(fldPath, initContainer.Name, "name clashes with the sidecar container"))
+		case containerNames[initContainer.Name]:
+			allErrs = append(allErrs, field.Duplicate(fldPath, initContainer.Name))
+		}
+		containerNames[initContainer.Name] = true
+	}
+
+	volumeNames := map[string]bool{}
+	for i, volume := range volumes {
+		fldPath := specPath.Child("volumes").Index(i).Child("name")
+		switch {
+		case volume.Name == "":
+			allErrs = append(allErrs, field.Required(fldPath, ""))
+		case reservedVolumeNames[volume.Name]:
+			allErrs = append(allErrs, field.Invalid(fldPath, volume.Name, "name is reserved by the operator"))
+		case volumeNames[volume.Name]:
+			allErrs = append(allErrs, field.Duplicate(fldPath, volume.Name))
+		}
+		volumeNames[volume.Name] = true
+	}
+
+	return allErrs
+}
+
+// validateVolumeMounts requires every sidecar and init container mount to reference a volume
+// declared in spec.volumes. The config volume is mounted by the operator and cannot be mounted again.
+func validateVolumeMounts(sidecar *SidecarConfig, initContainers []corev1.Container, volumes []corev1.Volume, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	declared := map[string]bool{}
+	for _, volume := range volumes {
+		declared[volume.Name] = true
+	}
+
+	checkMounts := func(mounts []corev1.VolumeMount, fldPath *field.Path) {
+		for i, mount := range mounts {
+			mountPath := fldPath.Index(i).Child("name")
+			switch {
+			case reservedVolumeNames[mount.Name]:
+				allErrs = append(allErrs, field.Invalid(mountPath, mount.Name, "volume is managed by the operator"))
+			case !declared[mount.Name]:
+				allErrs = append(allErrs, field.NotFound(mountPath, mount.Name))
+			}
+		}
+	}
+
+	checkMounts(sidecar.VolumeMounts, specPath.Child("sidecar", "volumeMounts"))
+	for i, initContainer := range initContainers {
+		checkMounts(initContainer.VolumeMounts, specPath.Child("initContainers").Index(i).Child("volumeMounts"))
+	}
+
+	return allErrs
+}
+
+// validateResources rejects negative quantities and requests that exceed their limits.
+// Quantities that do not parse are already rejected by the CRD schema.
+func validateResources(resources *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for name, quantity := range resources.Limits {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("limits").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+	}
+
+	for name, quantity := range resources.Requests {
+		requestPath := fldPath.Child("requests").Key(string(name))
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(requestPath, quantity.String(), "must be greater than or equal to 0"))
+			continue
+		}
+		if limit, ok := resources.Limits[name]; ok && quantity.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(requestPath, quantity.String(), "must be less than or equal to "+string(name)+" limit"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateIntervals rejects a negative unmatched grace period and a resync interval that is not positive
+func validateIntervals(gracePeriod, resyncInterval *metav1.Duration, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if gracePeriod != nil && gracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("unmatchedGracePeriod"), gracePeriod.Duration.String(), "must not be negative"))
+	}
+	if resyncInterval != nil && resyncInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("resyncInterval"), resyncInterval.Duration.String(), "must be positive"))
+	}
+
+	return allErrs
+}
+
+// validateRolloutStrategy requires a positive batch size, given as a count or a percentage
+// between 1% and 100%, and a non-negative pause
+func validateRolloutStrategy(strategy *RolloutStrategy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if strategy == nil {
+		return allErrs
+	}
+
+	if size := strategy.MaxBatchSize; size != nil {
+		sizePath := fldPath.Child("maxBatchSize")
+		if size.Type == intstr.Int {
+			if size.IntValue() < 1 {
+				allErrs = append(allErrs, field.Invalid(sizePath, size.String(), "must be greater than or equal to 1"))
+			}
+		} else {
+			percent, err := strconv.Atoi(strings.TrimSuffix(size.StrVal, "%"))
+			if !strings.HasSuffix(size.StrVal, "%") || err != nil || percent < 1 || percent > 100 {
+				allErrs = append(allErrs, field.Invalid(sizePath, size.String(), "must be an integer or a percentage between 1% and 100%"))
+			}
+		}
+	}
+
+	if pause := strategy.PauseBetweenBatches; pause != nil && pause.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pauseBetweenBatches"), pause.Duration.String(), "must not be negative"))
+	}
+
+	return allErrs
+}
+
+// validateRollbackPolicy requires a non-negative failure budget, given as a count or a percentage
+// between 0% and 100%
+func validateRollbackPolicy(policy *RollbackPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy == nil || policy.FailureBudget == nil {
+		return allErrs
+	}
+
+	budget := policy.FailureBudget
+	budgetPath := fldPath.Child("failureBudget")
+	if budget.Type == intstr.Int {
+		if budget.IntValue() < 0 {
+			allErrs = append(allErrs, field.Invalid(budgetPath, budget.String(), "must be greater than or equal to 0"))
+		}
+	} else {
+		percent, err := strconv.Atoi(strings.TrimSuffix(budget.StrVal, "%"))
+		if !strings.HasSuffix(budget.StrVal, "%") || err != nil || percent < 0 || percent > 100 {
+			allErrs = append(allErrs, field.Invalid(budgetPath, budget.String(), "must be an integer or a percentage between 0% and 100%"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the server-side apply field manager owning the fields the operator injects
+const FieldManager = "vector-sidecar-operator"
+
+// operatorAnnotations lists the workload annotations the operator sets
+var operatorAnnotations = []string{
+	AnnotationInjected,
+	AnnotationInjectedHash,
+	AnnotationVectorSidecarName,
+	AnnotationClusterVectorSidecarName,
+	AnnotationConfigMapVersion,
+	AnnotationInjectionManifest,
+}
+
+// operatorTemplateAnnotations lists the pod template annotations the operator sets
+var operatorTemplateAnnotations = []string{
+	AnnotationInjectedHash,
+	AnnotationConfigHash,
+}
+
+// injectionIntent builds the apply configuration of an injected workload: the given annotations
+// and the entries of the rendered pod spec listed in the manifest. Containers the shutdown signal
+// touches appear with only their name, the signal mount and the signal variable, so the
+// application's own fields stay owned by whoever manages them.
+func injectionIntent(wl *workload, podSpec *corev1.PodSpec, nativeSidecar string, manifest *injectionManifest,
+	annotations, templateAnnotations map[string]string) (*unstructured.Unstructured, error) {
+	shutdownSignal := wl.isBatch() && nativeSidecar == ""
+
+	intentSpec := corev1.PodSpec{}
+	for _, container := range podSpec.Containers {
+		if container.Name == manifest.Container {
+			intentSpec.Containers = append(intentSpec.Containers, container)
+			continue
+		}
+		if !shutdownSignal {
+			continue
+		}
+		stub := corev1.Container{Name: container.Name}
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == LifecycleVolumeName {
+				stub.VolumeMounts = append(stub.VolumeMounts, mount)
+			}
+		}
+		for _, envVar := range container.Env {
+			if envVar.Name == ShutdownFileEnvVar {
+				stub.Env = append(stub.Env, envVar)
+			}
+		}
+		intentSpec.Containers = append(intentSpec.Containers, stub)
+	}
+
+	for _, container := range podSpec.InitContainers {
+		if container.Name == nativeSidecar || contains(manifest.InitContainers, container.Name) {
+			intentSpec.InitContainers = append(intentSpec.InitContainers, container)
+		}
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if contains(manifest.Volumes, volume.Name) {
+			intentSpec.Volumes = append(intentSpec.Volumes, volume)
+		}
+	}
+	if manifest.ShareProcessNamespace {
+		intentSpec.ShareProcessNamespace = podSpec.ShareProcessNamespace
+	}
+
+	specJSON, err := json.Marshal(intentSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pod spec: %w", err)
+	}
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(specJSON, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode pod spec: %w", err)
+	}
+	pruneApplyFields(spec)
+
+	if nativeSidecar != "" {
+		// The typed Container of this client version cannot carry the restart policy
+		initContainers, _ := spec["initContainers"].([]interface{})
+		for _, item := range initContainers {
+			if container, ok := item.(map[string]interface{}); ok && container["name"] == nativeSidecar {
+				container["restartPolicy"] = "Always"
+			}
+		}
+	}
+
+	intent := emptyIntent(wl)
+	intent.SetAnnotations(annotations)
+
+	podSpecPath := wl.podSpecPath()
+	annotationsPath := append(append([]string{}, podSpecPath[:len(podSpecPath)-1]...), "metadata", "annotations")
+	templateMetadata := map[string]interface{}{}
+	for key, value := range templateAnnotations {
+		templateMetadata[key] = value
+	}
+	if err := unstructured.SetNestedMap(intent.Object, templateMetadata, annotationsPath...); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedMap(intent.Object, spec, podSpecPath...); err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// emptyIntent returns an apply configuration identifying the workload without claiming any
+// field. Applying it releases every field the operator owns.
+func emptyIntent(wl *workload) *unstructured.Unstructured {
+	intent := &unstructured.Unstructured{}
+	intent.SetGroupVersionKind(wl.groupVersionKind())
+	intent.SetName(wl.GetName())
+	intent.SetNamespace(wl.GetNamespace())
+	return intent
+}
+
+// pruneApplyFields drops null values and empty resource requirements from an encoded pod spec,
+// so the intent claims only the fields the operator sets
+func pruneApplyFields(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if field == nil {
+				delete(v, key)
+				continue
+			}
+			if resources, ok := field.(map[string]interface{}); ok && key == "resources" && len(resources) == 0 {
+				delete(v, key)
+				continue
+			}
+			pruneApplyFields(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			pruneApplyFields(item)
+		}
+	}
+}
+
+// applyWorkload server-side applies the intent under the operator's field manager, forcing
+// ownership of the fields it claims. Workloads injected before the operator used server-side
+// apply hold the injected fields under another manager, which an apply cannot release, so
+// tidy then adjusts the applied workload and any remaining difference is written as a
+// strategic merge patch.
+func (r *VectorSidecarReconciler) applyWorkload(ctx context.Context, wl *workload, intent *unstructured.Unstructured, tidy func(*workload)) error {
+	if err := r.waitForWorkloadWrite(ctx); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, intent, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	obj, err := newWorkloadObject(wl.Kind)
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(intent.Object, obj); err != nil {
+		return fmt.Errorf("failed to decode applied %s: %w", wl.Kind, err)
+	}
+	applied, err := newWorkload(obj)
+	if err != nil {
+		return err
+	}
+
+	tidied := applied.DeepCopy()
+	tidy(tidied)
+	patch, err := createPodTemplatePatch(applied.Object, tidied.Object, tidied.podSpecPath(), "")
+	if err != nil {
+		return err
+	}
+	if string(patch) == "{}" {
+		return nil
+	}
+	if err := r.waitForWorkloadWrite(ctx); err != nil {
+		return err
+	}
+	return r.Patch(ctx, tidied.Object, client.RawPatch(types.StrategicMergePatchType, patch))
+}
+
+// tidyInjection strips what the previous manifest recorded and the current one no longer lists,
+// then pins the Vector container to its placement: first among the init containers as a native
+// sidecar, last among the containers otherwise so kubectl logs and exec keep defaulting to the
+// application. Injected init containers follow the workload's own in manifest order.
+func tidyInjection(wl *workload, nativeSidecar string, manifest, previous *injectionManifest,
+	annotations, templateAnnotations map[string]string) {
+	if previous != nil {
+		previous.without(manifest).strip(wl.Template)
+	}
+
+	podSpec := &wl.Template.Spec
+	sidecarName := manifest.Container
+
+	var sidecar *corev1.Container
+	containers := []corev1.Container{}
+	for i, container := range podSpec.Containers {
+		if container.Name != sidecarName {
+			containers = append(containers, container)
+		} else if nativeSidecar == "" {
+			sidecar = &podSpec.Containers[i]
+		}
+	}
+	initContainers := []corev1.Container{}
+	for i, container := range podSpec.InitContainers {
+		if container.Name != sidecarName {
+			initContainers = append(initContainers, container)
+		} else if nativeSidecar != "" {
+			sidecar = &podSpec.InitContainers[i]
+		}
+	}
+	if sidecar != nil {
+		if nativeSidecar != "" {
+			initContainers = append([]corev1.Container{*sidecar}, initContainers...)
+		} else {
+			containers = append(containers, *sidecar)
+		}
+	}
+
+	// Injected init containers run after the workload's own, in manifest order
+	ordered := []corev1.Container{}
+	for _, container := range initContainers {
+		if !contains(manifest.InitContainers, container.Name) {
+			ordered = append(ordered, container)
+		}
+	}
+	for _, name := range manifest.InitContainers {
+		for _, container := range initContainers {
+			if container.Name == name {
+				ordered = append(ordered, container)
+				break
+			}
+		}
+	}
+
+	podSpec.Containers = containers
+	podSpec.InitContainers = ordered
+
+	if !wl.isBatch() || nativeSidecar != "" {
+		removeShutdownSignal(podSpec, sidecarName)
+	}
+
+	workloadAnnotations := wl.GetAnnotations()
+	for _, key := range operatorAnnotations {
+		if _, ok := annotations[key]; !ok {
+			delete(workloadAnnotations, key)
+		}
+	}
+	wl.SetAnnotations(workloadAnnotations)
+	for _, key := range operatorTemplateAnnotations {
+		if _, ok := templateAnnotations[key]; !ok {
+			delete(wl.Template.Annotations, key)
+		}
+	}
+}
+
+// tidyRemoval strips every entry of the manifest, the batch shutdown signal and the operator
+// annotations from a workload, restoring its pod template as it was before the injection
+func tidyRemoval(wl *workload, manifest *injectionManifest) {
+	if manifest != nil {
+		// Remove the batch shutdown signal before the Vector container goes away
+		removeShutdownSignal(&wl.Template.Spec, manifest.Container)
+		manifest.strip(wl.Template)
+	}
+
+	annotations := wl.GetAnnotations()
+	for _, key := range operatorAnnotations {
+		delete(annotations, key)
+	}
+	wl.SetAnnotations(annotations)
+	for _, key := range operatorTemplateAnnotations {
+		delete(wl.Template.Annotations, key)
+	}
+}
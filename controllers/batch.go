@@ -0,0 +1,176 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+const (
+	// LifecycleVolumeName is the shared emptyDir used to signal Vector that batch work is done
+	LifecycleVolumeName = observabilityv1alpha1.LifecycleVolumeName
+
+	// LifecycleMountPath is where the lifecycle volume is mounted in every container
+	LifecycleMountPath = "/var/run/vector-lifecycle"
+
+	// ShutdownFileEnvVar exposes the shutdown marker path to application containers
+	ShutdownFileEnvVar = "VECTOR_SHUTDOWN_FILE"
+)
+
+// shutdownFilePath is the marker application containers create when they finish
+var shutdownFilePath = LifecycleMountPath + "/done"
+
+// shutdownGracePeriod is how many seconds the application containers may have no process
+// before the wrapper stops Vector, so a container restarted by restartPolicy OnFailure is
+// not left without it
+const shutdownGracePeriod = 5
+
+// shutdownWrapperScript runs Vector in the background and stops it once the shutdown marker
+// appears or, through the shared process namespace, once the application processes are gone,
+// so the pod of a Job or CronJob completes even when the application crashes before creating
+// the marker. Vector drains its buffers on SIGTERM before exiting.
+var shutdownWrapperScript = fmt.Sprintf(`vector "$@" &
+pid=$!
+seen=0
+idle=0
+while [ ! -f %[1]s ]; do
+  if ! kill -0 "$pid" 2>/dev/null; then
+    wait "$pid"
+    exit $?
+  fi
+  others=0
+  for proc in /proc/[0-9]*; do
+    case "${proc#/proc/}" in
+      1|$$|$pid) ;;
+      *) others=1 ;;
+    esac
+  done
+  if [ "$others" = 1 ]; then
+    seen=1
+    idle=0
+  elif [ "$seen" = 1 ]; then
+    idle=$((idle + 1))
+    if [ "$idle" -ge %[2]d ]; then
+      break
+    fi
+  fi
+  sleep 1
+done
+kill -TERM "$pid"
+wait "$pid"`, shutdownFilePath, shutdownGracePeriod)
+
+// shutdownWrapperCommand runs Vector through the wrapper; the original args are passed through as "$@"
+var shutdownWrapperCommand = []string{"/bin/sh", "-c", shutdownWrapperScript, "vector"}
+
+// injectShutdownSignal wires the shared-volume shutdown signal between the application
+// containers and the Vector container of a batch pod, and shares the process namespace so
+// the wrapper sees the application exit. It is only used where native sidecars are not
+// supported, and needs a shell in the Vector image.
+func injectShutdownSignal(podSpec *corev1.PodSpec, sidecarName string) {
+	removeShutdownSignal(podSpec, sidecarName)
+
+	shareProcessNamespace := true
+	podSpec.ShareProcessNamespace = &shareProcessNamespace
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: LifecycleVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	lifecycleMount := corev1.VolumeMount{
+		Name:      LifecycleVolumeName,
+		MountPath: LifecycleMountPath,
+	}
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, lifecycleMount)
+
+		if container.Name == sidecarName {
+			container.Command = append([]string(nil), shutdownWrapperCommand...)
+			continue
+		}
+
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  ShutdownFileEnvVar,
+			Value: shutdownFilePath,
+		})
+	}
+}
+
+// removeShutdownSignal strips the lifecycle volume, mounts and environment added by injectShutdownSignal
+func removeShutdownSignal(podSpec *corev1.PodSpec, sidecarName string) {
+	volumes := []corev1.Volume{}
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != LifecycleVolumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = volumes
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+
+		mounts := []corev1.VolumeMount{}
+		for _, mount := range container.VolumeMounts {
+			if mount.Name != LifecycleVolumeName {
+				mounts = append(mounts, mount)
+			}
+		}
+		container.VolumeMounts = mounts
+
+		if container.Name == sidecarName {
+			continue
+		}
+
+		env := []corev1.EnvVar{}
+		for _, envVar := range container.Env {
+			if envVar.Name != ShutdownFileEnvVar {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+	}
+}
+
+// shutdownHandlingOutdated reports whether a batch pod spec injected with the current hash stops
+// Vector the way an earlier injection did: as a regular container although native sidecars
+// are now used, or behind another wrapper or without the shared process namespace
+func shutdownHandlingOutdated(podSpec *corev1.PodSpec, sidecarName string, native bool) bool {
+	for _, container := range podSpec.InitContainers {
+		if container.Name == sidecarName {
+			return !native
+		}
+	}
+	if native {
+		return true
+	}
+	for _, container := range podSpec.Containers {
+		if container.Name == sidecarName {
+			return !equality.Semantic.DeepEqual(container.Command, shutdownWrapperCommand) ||
+				podSpec.ShareProcessNamespace == nil || !*podSpec.ShareProcessNamespace
+		}
+	}
+	return false
+}
@@ -0,0 +1,653 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// clusterVectorSidecarKind marks the per-namespace VectorSidecar view of a ClusterVectorSidecar
+const clusterVectorSidecarKind = "ClusterVectorSidecar"
+
+// ClusterVectorSidecarReconciler reconciles a ClusterVectorSidecar object
+type ClusterVectorSidecarReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Injector renders the sidecar into workloads. It is shared with the VectorSidecar
+	// controller so both use the same injection code and native sidecar detection.
+	Injector *VectorSidecarReconciler
+
+	// Options tunes the workers and retry backoff of the controller
+	Options ControllerOptions
+}
+
+//+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=clustervectorsidecars,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=clustervectorsidecars/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=clustervectorsidecars/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile injects the Vector sidecar into matching workloads of every selected namespace.
+// A namespaced VectorSidecar selecting the same workload takes precedence. Among
+// ClusterVectorSidecars the highest priority wins and ties go to the name that sorts first.
+func (r *ClusterVectorSidecarReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling ClusterVectorSidecar", "name", req.Name)
+	start := time.Now()
+
+	clusterSidecar := &observabilityv1alpha1.ClusterVectorSidecar{}
+	if err := r.Get(ctx, req.NamespacedName, clusterSidecar); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ClusterVectorSidecar resource not found, ignoring")
+			forgetMetrics("", req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get ClusterVectorSidecar")
+		return ctrl.Result{}, err
+	}
+	defer observeReconcileDuration("", req.Name, start)
+
+	// Handle deletion with finalizer
+	if !clusterSidecar.DeletionTimestamp.IsZero() {
+		return r.handleDeletion(ctx, clusterSidecar)
+	}
+
+	// Add finalizer if not present
+	if !controllerutil.ContainsFinalizer(clusterSidecar, FinalizerName) {
+		controllerutil.AddFinalizer(clusterSidecar, FinalizerName)
+		if err := r.Update(ctx, clusterSidecar); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Resolve and validate the configuration that is copied into every selected namespace
+	configKey, configContent, err := r.resolveConfig(ctx, clusterSidecar)
+	if err == nil {
+		err = validateVectorConfig(configKey, configContent)
+	}
+	if err != nil {
+		logger.Error(err, "Invalid ClusterVectorSidecar configuration")
+		configValidationFailuresTotal.Inc()
+		r.setCondition(clusterSidecar, observabilityv1alpha1.ConditionTypeConfigValid,
+			metav1.ConditionFalse, "ValidationFailed", err.Error())
+		r.Recorder.Event(clusterSidecar, corev1.EventTypeWarning, "ValidationFailed", err.Error())
+
+		if statusErr := r.writeStatus(ctx, clusterSidecar); statusErr != nil {
+			logger.Error(statusErr, "Failed to update status after validation failure")
+			return ctrl.Result{}, statusErr
+		}
+
+		requeueAfter := clusterResyncInterval(clusterSidecar)
+		if requeueAfter > time.Minute {
+			requeueAfter = time.Minute
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	r.setCondition(clusterSidecar, observabilityv1alpha1.ConditionTypeConfigValid,
+		metav1.ConditionTrue, "ValidationSucceeded", "Configuration is valid")
+
+	namespaces, err := r.selectedNamespaces(ctx, clusterSidecar)
+	if err != nil {
+		logger.Error(err, "Failed to list selected namespaces")
+		return ctrl.Result{}, err
+	}
+
+	selected := map[string]bool{}
+	if clusterSidecar.Spec.Enabled {
+		for _, ns := range namespaces {
+			selected[ns.Name] = true
+		}
+	}
+
+	// Strip workloads and configuration from namespaces that are no longer selected
+	if err := r.cleanupUnselectedNamespaces(ctx, clusterSidecar, selected); err != nil {
+		logger.Error(err, "Failed to clean up unselected namespaces")
+		return ctrl.Result{}, err
+	}
+
+	if !clusterSidecar.Spec.Enabled {
+		logger.Info("ClusterVectorSidecar is disabled, removed sidecars from workloads")
+		clusterSidecar.Status.MatchedNamespaces = 0
+		clusterSidecar.Status.MatchedWorkloads = 0
+		clusterSidecar.Status.InjectedWorkloads = 0
+		clusterSidecar.Status.OverriddenWorkloads = 0
+		clusterSidecar.Status.UnmatchedTargets = nil
+		setConflictCondition(&clusterSidecar.Status.Conditions, clusterSidecar.Generation, nil)
+		r.setCondition(clusterSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionTrue, "SidecarDisabled", "Removed sidecars from all workloads")
+		return ctrl.Result{}, r.updateStatus(ctx, clusterSidecar)
+	}
+
+	var matchedCount, injectedCount int32
+	var conflicts []workloadConflict
+	var injectionErrors []string
+	var unmatchedTargets []observabilityv1alpha1.ClusterTargetStatus
+	requeueAfter := clusterResyncInterval(clusterSidecar)
+	now := time.Now()
+	for _, ns := range namespaces {
+		view := clusterSidecarView(clusterSidecar, ns.Name, configKey)
+
+		if err := r.reconcileClusterConfigMap(ctx, clusterSidecar, ns.Name, configKey, configContent); err != nil {
+			injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", ns.Name, err))
+			continue
+		}
+
+		workloads, err := r.Injector.getMatchingWorkloads(ctx, view)
+		if err != nil {
+			injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", ns.Name, err))
+			continue
+		}
+
+		// Workloads of the namespace that stopped matching lose the sidecar after a grace period
+		view.Status.Targets = namespaceTargets(clusterSidecar.Status.UnmatchedTargets, ns.Name)
+		cleanup, err := r.Injector.reconcileUnmatched(ctx, view, clusterSidecar, workloads, now)
+		if err != nil {
+			injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", ns.Name, err))
+			continue
+		}
+		injectionErrors = append(injectionErrors, cleanup.errors...)
+		for _, target := range cleanup.targets {
+			if target.Phase == observabilityv1alpha1.TargetPhaseUnmatched {
+				unmatchedTargets = append(unmatchedTargets, observabilityv1alpha1.ClusterTargetStatus{Namespace: ns.Name, TargetStatus: target})
+			}
+		}
+		if cleanup.requeueAfter > 0 && cleanup.requeueAfter < requeueAfter {
+			requeueAfter = cleanup.requeueAfter
+		}
+
+		for _, wl := range workloads {
+			matchedCount++
+
+			winner, err := r.workloadWinner(ctx, clusterSidecar, wl)
+			if err != nil {
+				injectionErrors = append(injectionErrors, fmt.Sprintf("%s/%s: %v", ns.Name, wl, err))
+				continue
+			}
+			if winner != "" {
+				logger.Info("Workload is injected by another sidecar resource, skipping",
+					"namespace", ns.Name, "workload", wl.String(), "winner", winner)
+				conflicts = append(conflicts, workloadConflict{workload: wl, winner: winner})
+				continue
+			}
+
+			if err := r.Injector.injectSidecar(ctx, view, wl); err != nil {
+				logger.Error(err, "Failed to inject sidecar", "namespace", ns.Name, "workload", wl.String())
+				injectionErrors = append(injectionErrors, fmt.Sprintf("%s/%s: %v", ns.Name, wl, err))
+				r.Recorder.Event(clusterSidecar, corev1.EventTypeWarning, "InjectionFailed",
+					fmt.Sprintf("Failed to inject into %s/%s: %v", ns.Name, wl, err))
+				continue
+			}
+			injectedCount++
+		}
+	}
+
+	clusterSidecar.Status.MatchedNamespaces = int32(len(namespaces))
+	clusterSidecar.Status.MatchedWorkloads = matchedCount
+	clusterSidecar.Status.InjectedWorkloads = injectedCount
+	clusterSidecar.Status.OverriddenWorkloads = int32(len(conflicts))
+	clusterSidecar.Status.UnmatchedTargets = unmatchedTargets
+	setConflictCondition(&clusterSidecar.Status.Conditions, clusterSidecar.Generation, conflicts)
+
+	if len(injectionErrors) > 0 {
+		r.setCondition(clusterSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionFalse, "InjectionPartiallyFailed",
+			fmt.Sprintf("Injected %d/%d workloads, errors: %v", injectedCount, matchedCount, injectionErrors))
+	} else {
+		r.setCondition(clusterSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionTrue, "InjectionSucceeded",
+			fmt.Sprintf("Injected %d workloads across %d namespaces", injectedCount, len(namespaces)))
+	}
+
+	if err := r.updateStatus(ctx, clusterSidecar); err != nil {
+		logger.Error(err, "Failed to update ClusterVectorSidecar status")
+		return ctrl.Result{}, err
+	}
+
+	if len(injectionErrors) > 0 && requeueAfter > time.Minute {
+		requeueAfter = time.Minute
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// handleDeletion strips the sidecar from every workload this ClusterVectorSidecar injected
+func (r *ClusterVectorSidecarReconciler) handleDeletion(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if controllerutil.ContainsFinalizer(clusterSidecar, FinalizerName) {
+		logger.Info("Removing sidecars from all workloads before deletion")
+
+		if err := r.cleanupUnselectedNamespaces(ctx, clusterSidecar, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		controllerutil.RemoveFinalizer(clusterSidecar, FinalizerName)
+		if err := r.Update(ctx, clusterSidecar); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveConfig returns the key and content of the configuration source. A ConfigMap
+// reference is read from the namespace it names, since the resource has none of its own.
+func (r *ClusterVectorSidecarReconciler) resolveConfig(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) (string, string, error) {
+	config := clusterSidecar.Spec.Sidecar.Config
+	if config.ConfigMapRef == nil {
+		if config.Inline == "" {
+			return "", "", fmt.Errorf("either configMapRef or inline configuration must be specified")
+		}
+		return InlineConfigKey, config.Inline, nil
+	}
+
+	if config.ConfigMapRef.Namespace == "" {
+		return "", "", fmt.Errorf("configMapRef.namespace must be set on a ClusterVectorSidecar")
+	}
+
+	key := config.ConfigMapRef.Key
+	if key == "" {
+		key = "vector.yaml"
+	}
+
+	cm := &corev1.ConfigMap{}
+	cmName := types.NamespacedName{Name: config.ConfigMapRef.Name, Namespace: config.ConfigMapRef.Namespace}
+	if err := r.Get(ctx, cmName, cm); err != nil {
+		return "", "", fmt.Errorf("configMap %s not found: %w", cmName, err)
+	}
+	content, ok := cm.Data[key]
+	if !ok {
+		return "", "", fmt.Errorf("configMap %s does not contain key %s", cmName, key)
+	}
+	return key, content, nil
+}
+
+// selectedNamespaces returns the namespaces matching the namespace selector, sorted by name
+func (r *ClusterVectorSidecarReconciler) selectedNamespaces(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) ([]corev1.Namespace, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&clusterSidecar.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make([]corev1.Namespace, 0, len(namespaceList.Items))
+	for _, ns := range namespaceList.Items {
+		if ns.DeletionTimestamp.IsZero() {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces, nil
+}
+
+// cleanupUnselectedNamespaces strips the sidecar from injected workloads and deletes the copied
+// configuration in every namespace missing from selected. A nil map cleans up everywhere.
+func (r *ClusterVectorSidecarReconciler) cleanupUnselectedNamespaces(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar, selected map[string]bool) error {
+	logger := log.FromContext(ctx)
+
+	for _, kind := range supportedWorkloadKinds {
+		workloads, err := r.Injector.listWorkloads(ctx, kind, client.MatchingFields{clusterSidecarNameIndexField: clusterSidecar.Name})
+		if err != nil {
+			return err
+		}
+
+		for _, wl := range workloads {
+			if selected[wl.GetNamespace()] {
+				continue
+			}
+
+			view := clusterSidecarView(clusterSidecar, wl.GetNamespace(), InlineConfigKey)
+			if err := r.Injector.removeSidecar(ctx, view, wl); err != nil {
+				logger.Error(err, "Failed to remove sidecar", "namespace", wl.GetNamespace(), "workload", wl.String())
+				return err
+			}
+			r.Recorder.Event(clusterSidecar, corev1.EventTypeNormal, "SidecarRemoved",
+				fmt.Sprintf("Removed sidecar from %s/%s", wl.GetNamespace(), wl))
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMaps, client.MatchingFields{clusterSidecarNameIndexField: clusterSidecar.Name}); err != nil {
+		return fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if selected[cm.Namespace] {
+			continue
+		}
+		if err := r.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileClusterConfigMap copies the configuration into the namespace so injected pods can mount it
+func (r *ClusterVectorSidecarReconciler) reconcileClusterConfigMap(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar,
+	namespace, configKey, content string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterConfigMapName(clusterSidecar.Name),
+			Namespace: namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[AnnotationClusterVectorSidecarName] = clusterSidecar.Name
+		cm.Data = map[string]string{vectorConfigFileName(configKey): content}
+		return controllerutil.SetControllerReference(clusterSidecar, cm, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile ConfigMap %s/%s: %w", namespace, cm.Name, err)
+	}
+	return nil
+}
+
+// workloadWinner describes the resource that injects the workload instead of the ClusterVectorSidecar,
+// or returns an empty string when the ClusterVectorSidecar wins. Namespaced VectorSidecars take
+// precedence over every ClusterVectorSidecar.
+func (r *ClusterVectorSidecarReconciler) workloadWinner(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar, wl *workload) (string, error) {
+	vectorSidecar, err := r.Injector.workloadWinner(ctx, wl)
+	if err != nil {
+		return "", err
+	}
+	if vectorSidecar != nil {
+		return fmt.Sprintf("VectorSidecar %s/%s", vectorSidecar.Namespace, vectorSidecar.Name), nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: wl.GetNamespace()}, ns); err != nil {
+		return "", fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	clusterSidecars := &observabilityv1alpha1.ClusterVectorSidecarList{}
+	if err := r.List(ctx, clusterSidecars); err != nil {
+		return "", fmt.Errorf("failed to list ClusterVectorSidecars: %w", err)
+	}
+	sort.Slice(clusterSidecars.Items, func(i, j int) bool {
+		return precedes(clusterSidecars.Items[i].Spec.Priority, clusterSidecars.Items[i].Name,
+			clusterSidecars.Items[j].Spec.Priority, clusterSidecars.Items[j].Name)
+	})
+	for i := range clusterSidecars.Items {
+		candidate := &clusterSidecars.Items[i]
+		if candidate.Name == clusterSidecar.Name {
+			return "", nil
+		}
+		if clusterSelectsWorkload(candidate, ns, wl) {
+			return fmt.Sprintf("ClusterVectorSidecar %s (priority %d)", candidate.Name, candidate.Spec.Priority), nil
+		}
+	}
+
+	return "", nil
+}
+
+// clusterSelectsWorkload reports whether an enabled ClusterVectorSidecar selects the namespace and workload
+func clusterSelectsWorkload(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar, ns *corev1.Namespace, wl *workload) bool {
+	if !clusterSidecar.DeletionTimestamp.IsZero() || !clusterSidecar.Spec.Enabled {
+		return false
+	}
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(&clusterSidecar.Spec.NamespaceSelector)
+	if err != nil || !namespaceSelector.Matches(labels.Set(ns.Labels)) {
+		return false
+	}
+
+	return selectsWorkload(clusterSidecarView(clusterSidecar, ns.Name, InlineConfigKey), wl)
+}
+
+// clusterSidecarView returns the VectorSidecar a ClusterVectorSidecar behaves as in one namespace.
+// Its configuration points at the copy made by reconcileClusterConfigMap.
+func clusterSidecarView(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar, namespace, configKey string) *observabilityv1alpha1.VectorSidecar {
+	spec := clusterSidecar.Spec.DeepCopy()
+
+	sidecar := spec.Sidecar
+	sidecar.Config = observabilityv1alpha1.VectorConfig{
+		ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{
+			Name: clusterConfigMapName(clusterSidecar.Name),
+			Key:  vectorConfigFileName(configKey),
+		},
+	}
+
+	return &observabilityv1alpha1.VectorSidecar{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: observabilityv1alpha1.GroupVersion.String(),
+			Kind:       clusterVectorSidecarKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterSidecar.Name,
+			Namespace: namespace,
+		},
+		Spec: observabilityv1alpha1.VectorSidecarSpec{
+			Enabled:              spec.Enabled,
+			Selector:             spec.Selector,
+			TargetKinds:          spec.TargetKinds,
+			InjectionStrategy:    observabilityv1alpha1.InjectionStrategyWorkload,
+			Sidecar:              sidecar,
+			ConfigReloadPolicy:   spec.ConfigReloadPolicy,
+			InitContainers:       spec.InitContainers,
+			Volumes:              spec.Volumes,
+			UnmatchedGracePeriod: spec.UnmatchedGracePeriod,
+			ResyncInterval:       spec.ResyncInterval,
+		},
+	}
+}
+
+// clusterResyncInterval returns how long a reconciled ClusterVectorSidecar waits before it is checked again
+func clusterResyncInterval(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) time.Duration {
+	if interval := clusterSidecar.Spec.ResyncInterval; interval != nil && interval.Duration > 0 {
+		return interval.Duration
+	}
+	return defaultResyncInterval
+}
+
+// namespaceTargets returns the targets of one namespace in the form a namespace view reports them
+func namespaceTargets(targets []observabilityv1alpha1.ClusterTargetStatus, namespace string) []observabilityv1alpha1.TargetStatus {
+	var namespaced []observabilityv1alpha1.TargetStatus
+	for _, target := range targets {
+		if target.Namespace == namespace {
+			namespaced = append(namespaced, target.TargetStatus)
+		}
+	}
+	return namespaced
+}
+
+// clusterConfigMapName returns the name of the ConfigMap copied into each selected namespace
+func clusterConfigMapName(clusterSidecarName string) string {
+	return fmt.Sprintf("%s-cluster-config", clusterSidecarName)
+}
+
+// isClusterScoped reports whether the VectorSidecar is the namespace view of a ClusterVectorSidecar
+func isClusterScoped(vectorSidecar *observabilityv1alpha1.VectorSidecar) bool {
+	return vectorSidecar.Kind == clusterVectorSidecarKind
+}
+
+// ownerAnnotation returns the workload annotation recording which resource injected the sidecar
+func ownerAnnotation(vectorSidecar *observabilityv1alpha1.VectorSidecar) string {
+	if isClusterScoped(vectorSidecar) {
+		return AnnotationClusterVectorSidecarName
+	}
+	return AnnotationVectorSidecarName
+}
+
+// setCondition updates or adds a condition to the status
+func (r *ClusterVectorSidecarReconciler) setCondition(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar,
+	conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&clusterSidecar.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: clusterSidecar.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateStatus stamps the observed generation and writes the status
+func (r *ClusterVectorSidecarReconciler) updateStatus(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) error {
+	clusterSidecar.Status.ObservedGeneration = clusterSidecar.Generation
+	return r.writeStatus(ctx, clusterSidecar)
+}
+
+// clusterSidecarsForNamespace enqueues every ClusterVectorSidecar, since a label change on
+// a namespace can add it to or remove it from any namespace selector
+func (r *ClusterVectorSidecarReconciler) clusterSidecarsForNamespace(obj client.Object) []reconcile.Request {
+	return r.clusterSidecarRequests(func(*observabilityv1alpha1.ClusterVectorSidecar) bool { return true })
+}
+
+// clusterSidecarsForConfigMap enqueues the ClusterVectorSidecars whose configMapRef names the ConfigMap
+func (r *ClusterVectorSidecarReconciler) clusterSidecarsForConfigMap(obj client.Object) []reconcile.Request {
+	return r.clusterSidecarRequests(func(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) bool {
+		ref := clusterSidecar.Spec.Sidecar.Config.ConfigMapRef
+		return ref != nil && ref.Name == obj.GetName() && ref.Namespace == obj.GetNamespace()
+	})
+}
+
+// clusterSidecarsForWorkload enqueues the ClusterVectorSidecars that injected or select the workload
+func (r *ClusterVectorSidecarReconciler) clusterSidecarsForWorkload(obj client.Object) []reconcile.Request {
+	wl, err := newWorkload(obj)
+	if err != nil {
+		return nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		return nil
+	}
+
+	return r.clusterSidecarRequests(func(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) bool {
+		return wl.GetAnnotations()[AnnotationClusterVectorSidecarName] == clusterSidecar.Name ||
+			clusterSelectsWorkload(clusterSidecar, ns, wl)
+	})
+}
+
+// clusterSidecarsForVectorSidecar enqueues every ClusterVectorSidecar selecting the namespace of a
+// VectorSidecar, so workloads a namespaced resource releases or claims are picked up or left alone
+func (r *ClusterVectorSidecarReconciler) clusterSidecarsForVectorSidecar(obj client.Object) []reconcile.Request {
+	ns := &corev1.Namespace{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		return nil
+	}
+
+	return r.clusterSidecarRequests(func(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) bool {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(&clusterSidecar.Spec.NamespaceSelector)
+		return err == nil && namespaceSelector.Matches(labels.Set(ns.Labels))
+	})
+}
+
+// clusterSidecarsInConflict enqueues the other ClusterVectorSidecars that lost a workload,
+// so they take it over when the winner steps back
+func (r *ClusterVectorSidecarReconciler) clusterSidecarsInConflict(obj client.Object) []reconcile.Request {
+	return r.clusterSidecarRequests(func(clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) bool {
+		return clusterSidecar.Name != obj.GetName() &&
+			meta.IsStatusConditionTrue(clusterSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeConflict)
+	})
+}
+
+// clusterSidecarRequests lists the ClusterVectorSidecars and enqueues those accepted by match
+func (r *ClusterVectorSidecarReconciler) clusterSidecarRequests(match func(*observabilityv1alpha1.ClusterVectorSidecar) bool) []reconcile.Request {
+	ctx := context.Background()
+
+	clusterSidecars := &observabilityv1alpha1.ClusterVectorSidecarList{}
+	if err := r.List(ctx, clusterSidecars); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list ClusterVectorSidecars")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range clusterSidecars.Items {
+		if match(&clusterSidecars.Items[i]) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: clusterSidecars.Items[i].Name},
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterVectorSidecarReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Index the copied ConfigMaps by the ClusterVectorSidecar they belong to, so namespace
+	// cleanup does not list every ConfigMap in the cluster
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.ConfigMap{},
+		clusterSidecarNameIndexField, indexAnnotation(AnnotationClusterVectorSidecarName)); err != nil {
+		return err
+	}
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.Options.controllerOptions()).
+		For(&observabilityv1alpha1.ClusterVectorSidecar{}).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSidecarsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSidecarsForConfigMap)).
+		Watches(&source.Kind{Type: &observabilityv1alpha1.VectorSidecar{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSidecarsForVectorSidecar)).
+		Watches(&source.Kind{Type: &observabilityv1alpha1.ClusterVectorSidecar{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSidecarsInConflict),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	// Status-only workload updates never change what is injected
+	for _, kind := range supportedWorkloadKinds {
+		obj, err := newWorkloadObject(kind)
+		if err != nil {
+			return err
+		}
+		bldr = bldr.Watches(&source.Kind{Type: obj},
+			handler.EnqueueRequestsFromMapFunc(r.clusterSidecarsForWorkload),
+			builder.WithPredicates(predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			)))
+	}
+
+	return bldr.Complete(r)
+}
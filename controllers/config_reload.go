@@ -0,0 +1,101 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+const (
+	// AnnotationConfigHash stores the hash of the Vector configuration on the pod template,
+	// so a content change rolls the pods under the RestartPods reload policy
+	AnnotationConfigHash = "vectorsidecar.observability.kontroloop.ai/config-hash"
+
+	// configMapRefIndexField indexes VectorSidecars by the name of the ConfigMap they reference
+	configMapRefIndexField = ".spec.sidecar.config.configMapRef.name"
+)
+
+// configReloadPolicy returns the reload policy of the VectorSidecar, defaulting to RestartPods
+func configReloadPolicy(vectorSidecar *observabilityv1alpha1.VectorSidecar) observabilityv1alpha1.ConfigReloadPolicy {
+	if vectorSidecar.Spec.ConfigReloadPolicy == "" {
+		return observabilityv1alpha1.ConfigReloadPolicyRestartPods
+	}
+	return vectorSidecar.Spec.ConfigReloadPolicy
+}
+
+// configContentHash returns the hash of the Vector configuration the sidecar mounts. It is
+// empty under the HotReload policy, where content changes are left to Vector and must not
+// roll the pods.
+func (r *VectorSidecarReconciler) configContentHash(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (string, error) {
+	if configReloadPolicy(vectorSidecar) == observabilityv1alpha1.ConfigReloadPolicyHotReload {
+		return "", nil
+	}
+
+	content := vectorSidecar.Spec.Sidecar.Config.Inline
+	if ref := vectorSidecar.Spec.Sidecar.Config.ConfigMapRef; ref != nil {
+		cm := &corev1.ConfigMap{}
+		cmName := types.NamespacedName{Name: ref.Name, Namespace: vectorSidecar.Namespace}
+		if err := r.Get(ctx, cmName, cm); err != nil {
+			return "", fmt.Errorf("failed to get configMap %s: %w", cmName.Name, err)
+		}
+		content = cm.Data[configSourceKey(vectorSidecar)]
+	}
+
+	hash := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("%x", hash[:8]), nil
+}
+
+// indexConfigMapRef is the field indexer behind configMapRefIndexField
+func indexConfigMapRef(obj client.Object) []string {
+	vectorSidecar, ok := obj.(*observabilityv1alpha1.VectorSidecar)
+	if !ok || vectorSidecar.Spec.Sidecar.Config.ConfigMapRef == nil {
+		return nil
+	}
+	return []string{vectorSidecar.Spec.Sidecar.Config.ConfigMapRef.Name}
+}
+
+// vectorSidecarsForConfigMap maps a ConfigMap event to the VectorSidecars referencing it
+func (r *VectorSidecarReconciler) vectorSidecarsForConfigMap(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	vectorSidecars := &observabilityv1alpha1.VectorSidecarList{}
+	if err := r.List(ctx, vectorSidecars,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{configMapRefIndexField: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VectorSidecars for ConfigMap",
+			"namespace", obj.GetNamespace(), "name", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(vectorSidecars.Items))
+	for _, vectorSidecar := range vectorSidecars.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vectorSidecar.Name, Namespace: vectorSidecar.Namespace},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// workloadConflict records a selected workload that is injected by another resource
+type workloadConflict struct {
+	workload *workload
+	winner   string
+}
+
+func (c workloadConflict) String() string {
+	return fmt.Sprintf("%s is injected by %s", c.workload, c.winner)
+}
+
+// precedes reports whether a wins over b when both select a workload: the higher
+// priority wins and ties go to the name that sorts first
+func precedes(aPriority int32, aName string, bPriority int32, bName string) bool {
+	if aPriority != bPriority {
+		return aPriority > bPriority
+	}
+	return aName < bName
+}
+
+// sortVectorSidecarsByPrecedence orders VectorSidecars from the highest precedence down
+func sortVectorSidecarsByPrecedence(vectorSidecars []observabilityv1alpha1.VectorSidecar) {
+	sort.Slice(vectorSidecars, func(i, j int) bool {
+		return precedes(vectorSidecars[i].Spec.Priority, vectorSidecars[i].Name,
+			vectorSidecars[j].Spec.Priority, vectorSidecars[j].Name)
+	})
+}
+
+// workloadWinner returns the enabled VectorSidecar with the highest precedence among those
+// in the workload's namespace selecting it, or nil when none does. A VectorSidecar in dry run
+// keeps the workloads it injected but is left out for the others, so it neither takes a
+// workload over nor hands its own to the next VectorSidecar.
+func (r *VectorSidecarReconciler) workloadWinner(ctx context.Context, wl *workload) (*observabilityv1alpha1.VectorSidecar, error) {
+	vectorSidecars := &observabilityv1alpha1.VectorSidecarList{}
+	if err := r.List(ctx, vectorSidecars, client.InNamespace(wl.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list VectorSidecars: %w", err)
+	}
+	sortVectorSidecarsByPrecedence(vectorSidecars.Items)
+
+	for i := range vectorSidecars.Items {
+		vectorSidecar := &vectorSidecars.Items[i]
+		if !vectorSidecar.DeletionTimestamp.IsZero() || !vectorSidecar.Spec.Enabled {
+			continue
+		}
+		if dryRun(vectorSidecar) && wl.GetAnnotations()[AnnotationVectorSidecarName] != vectorSidecar.Name {
+			continue
+		}
+		if selectsWorkload(vectorSidecar, wl) {
+			return vectorSidecar, nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveConflicts splits the workloads selected by the VectorSidecar into those it injects
+// and those another VectorSidecar with higher precedence injects
+func (r *VectorSidecarReconciler) resolveConflicts(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	workloads []*workload) ([]*workload, []workloadConflict, error) {
+	var won []*workload
+	var conflicts []workloadConflict
+	for _, wl := range workloads {
+		winner, err := r.workloadWinner(ctx, wl)
+		if err != nil {
+			return nil, nil, err
+		}
+		if winner == nil || winner.Name == vectorSidecar.Name {
+			won = append(won, wl)
+			continue
+		}
+		conflicts = append(conflicts, workloadConflict{
+			workload: wl,
+			winner:   fmt.Sprintf("VectorSidecar %s (priority %d)", winner.Name, winner.Spec.Priority),
+		})
+	}
+	return won, conflicts, nil
+}
+
+// setConflictCondition reports the workloads left to a resource with higher precedence
+func setConflictCondition(conditions *[]metav1.Condition, generation int64, conflicts []workloadConflict) {
+	condition := metav1.Condition{
+		Type:               observabilityv1alpha1.ConditionTypeConflict,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoConflict",
+		Message:            "No selected workload is injected by another resource",
+	}
+	if len(conflicts) > 0 {
+		messages := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			messages = append(messages, conflict.String())
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "WorkloadConflict"
+		condition.Message = strings.Join(messages, "; ")
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// vectorSidecarsInConflict maps a VectorSidecar event to the other VectorSidecars of the
+// namespace that lost a workload, so they take it over when the winner steps back
+func (r *VectorSidecarReconciler) vectorSidecarsInConflict(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	vectorSidecars := &observabilityv1alpha1.VectorSidecarList{}
+	if err := r.List(ctx, vectorSidecars, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VectorSidecars in conflict", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, vectorSidecar := range vectorSidecars.Items {
+		if vectorSidecar.Name == obj.GetName() ||
+			!meta.IsStatusConditionTrue(vectorSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeConflict) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vectorSidecar.Name, Namespace: vectorSidecar.Namespace},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// injectionDrifted reports whether a workload annotated with the current hash lost part of the
+// recorded injection, as when a manifest re-applied by hand or by a GitOps tool drops the Vector
+// container or the pod template annotations
+func injectionDrifted(vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload, hash string) bool {
+	if wl.Template.Annotations[AnnotationInjectedHash] != hash {
+		return true
+	}
+	manifest := recordedManifest(vectorSidecar, wl)
+	if manifest == nil {
+		return true
+	}
+
+	podSpec := wl.Template.Spec
+	containers := map[string]bool{}
+	for _, container := range podSpec.Containers {
+		containers[container.Name] = true
+	}
+	for _, container := range podSpec.InitContainers {
+		containers[container.Name] = true
+	}
+	volumes := map[string]bool{}
+	for _, volume := range podSpec.Volumes {
+		volumes[volume.Name] = true
+	}
+
+	if !containers[manifest.Container] {
+		return true
+	}
+	for _, name := range manifest.InitContainers {
+		if !containers[name] {
+			return true
+		}
+	}
+	for _, name := range manifest.Volumes {
+		if !volumes[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// reportDrift records that the injection of a workload was changed outside the operator and is
+// about to be re-applied. The workload watch reconciles the VectorSidecar recorded on the workload
+// as soon as it changes, so a removed sidecar comes back without waiting for the resync.
+func (r *VectorSidecarReconciler) reportDrift(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload, hash string) {
+	log.FromContext(ctx).Info("Injected sidecar was changed outside the operator, re-applying it",
+		"workload", wl.String(), "hash", hash)
+	driftDetectedTotal.Inc()
+	r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "DriftDetected",
+		fmt.Sprintf("%s no longer carries the injected sidecar, re-applying it", wl))
+}
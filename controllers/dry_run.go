@@ -0,0 +1,205 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// dryRun reports whether the VectorSidecar only plans its changes. A VectorSidecar in dry run
+// never claims a workload from another one, and keeps the workloads it injected.
+func dryRun(vectorSidecar *observabilityv1alpha1.VectorSidecar) bool {
+	return vectorSidecar.Spec.Mode == observabilityv1alpha1.ReconcileModeDryRun
+}
+
+// handleDryRun publishes the changes the VectorSidecar would make in status.plan, with a
+// summary Event whenever the plan changes, without writing to any workload or ConfigMap
+func (r *VectorSidecarReconciler) handleDryRun(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	plan, err := r.planChanges(ctx, vectorSidecar)
+	if err != nil {
+		logger.Error(err, "Failed to plan the dry run")
+		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionFalse, "PlanFailed", err.Error())
+		if statusErr := r.writeStatus(ctx, vectorSidecar); statusErr != nil {
+			logger.Error(statusErr, "Failed to update status after planning failure")
+		}
+		return ctrl.Result{}, err
+	}
+
+	changed := !equality.Semantic.DeepEqual(vectorSidecar.Status.Plan, plan)
+	summary := planSummary(plan)
+	vectorSidecar.Status.Plan = plan
+	vectorSidecar.Status.ObservedGeneration = vectorSidecar.Generation
+	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+		metav1.ConditionFalse, "DryRun", summary)
+
+	if err := r.writeStatus(ctx, vectorSidecar); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	if changed {
+		r.Recorder.Event(vectorSidecar, corev1.EventTypeNormal, "DryRunPlanned", summary)
+	}
+
+	logger.Info("Dry run complete", "injections", plan.Injections, "updates", plan.Updates,
+		"removals", plan.Removals, "podRestarts", plan.PodRestarts)
+	return ctrl.Result{RequeueAfter: resyncInterval(vectorSidecar)}, nil
+}
+
+// planChanges computes what reconciling the VectorSidecar in Apply mode would do: inject the
+// matched workloads it wins that carry no injection, update those carrying another hash, strip
+// the injected workloads it no longer matches and strip every injected workload when it is
+// disabled or injects pods instead
+func (r *VectorSidecarReconciler) planChanges(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (*observabilityv1alpha1.DryRunPlan, error) {
+	plan := &observabilityv1alpha1.DryRunPlan{
+		CPURequests:    *resource.NewMilliQuantity(0, resource.DecimalSI),
+		MemoryRequests: *resource.NewQuantity(0, resource.BinarySI),
+	}
+
+	if vectorSidecar.Spec.Enabled && vectorSidecar.Spec.InjectionStrategy != observabilityv1alpha1.InjectionStrategyPod {
+		matchedWorkloads, err := r.getMatchingWorkloads(ctx, vectorSidecar)
+		if err != nil {
+			return nil, err
+		}
+		ownedWorkloads, _, err := r.resolveConflicts(ctx, vectorSidecar, matchedWorkloads)
+		if err != nil {
+			return nil, err
+		}
+		desiredHash, err := r.desiredInjectionHash(ctx, vectorSidecar)
+		if err != nil {
+			return nil, err
+		}
+		plan.Hash = desiredHash
+
+		sidecar := r.buildVectorContainer(vectorSidecar)
+		for _, wl := range ownedWorkloads {
+			appliedHash := appliedInjectionHash(vectorSidecar, wl)
+			if appliedHash == desiredHash {
+				continue
+			}
+			action := observabilityv1alpha1.PlannedActionUpdate
+			if appliedHash == "" {
+				action = observabilityv1alpha1.PlannedActionInject
+			}
+			addPlannedAction(plan, plannedAction(vectorSidecar, wl, action, appliedHash, desiredHash, &sidecar))
+		}
+
+		unmatchedWorkloads, err := r.unmatchedWorkloads(ctx, vectorSidecar, matchedWorkloads)
+		if err != nil {
+			return nil, err
+		}
+		for _, wl := range unmatchedWorkloads {
+			addPlannedAction(plan, plannedAction(vectorSidecar, wl, observabilityv1alpha1.PlannedActionStrip,
+				appliedInjectionHash(vectorSidecar, wl), "", nil))
+		}
+	} else {
+		injectedWorkloads, err := r.getInjectedWorkloads(ctx, vectorSidecar)
+		if err != nil {
+			return nil, err
+		}
+		for _, wl := range injectedWorkloads {
+			addPlannedAction(plan, plannedAction(vectorSidecar, wl, observabilityv1alpha1.PlannedActionStrip,
+				appliedInjectionHash(vectorSidecar, wl), "", nil))
+		}
+	}
+
+	sort.Slice(plan.Actions, func(i, j int) bool {
+		if plan.Actions[i].Kind != plan.Actions[j].Kind {
+			return plan.Actions[i].Kind < plan.Actions[j].Kind
+		}
+		return plan.Actions[i].Name < plan.Actions[j].Name
+	})
+	return plan, nil
+}
+
+// plannedAction describes the change to one workload. The request changes compare the Vector
+// container recorded on the workload with the rendered one, across the pods the change replaces.
+func plannedAction(vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload, action observabilityv1alpha1.PlannedActionType,
+	oldHash, newHash string, sidecar *corev1.Container) observabilityv1alpha1.PlannedAction {
+	var before, after corev1.ResourceList
+	if manifest := recordedManifest(vectorSidecar, wl); manifest != nil && action != observabilityv1alpha1.PlannedActionInject {
+		before = containerRequests(&wl.Template.Spec, manifest.Container)
+	}
+	if sidecar != nil {
+		after = sidecar.Resources.Requests
+	}
+
+	pods := wl.restartedPods()
+	cpu := (after.Cpu().MilliValue() - before.Cpu().MilliValue()) * int64(pods)
+	memory := (after.Memory().Value() - before.Memory().Value()) * int64(pods)
+	return observabilityv1alpha1.PlannedAction{
+		Kind:           wl.Kind,
+		Name:           wl.GetName(),
+		Action:         action,
+		OldHash:        oldHash,
+		NewHash:        newHash,
+		PodRestarts:    pods,
+		CPURequests:    *resource.NewMilliQuantity(cpu, resource.DecimalSI),
+		MemoryRequests: *resource.NewQuantity(memory, resource.BinarySI),
+	}
+}
+
+// containerRequests returns the resource requests of the named container in either placement
+func containerRequests(podSpec *corev1.PodSpec, name string) corev1.ResourceList {
+	for _, containers := range [][]corev1.Container{podSpec.Containers, podSpec.InitContainers} {
+		for _, container := range containers {
+			if container.Name == name {
+				return container.Resources.Requests
+			}
+		}
+	}
+	return nil
+}
+
+// addPlannedAction appends the action to the plan and adds it to the totals
+func addPlannedAction(plan *observabilityv1alpha1.DryRunPlan, action observabilityv1alpha1.PlannedAction) {
+	switch action.Action {
+	case observabilityv1alpha1.PlannedActionInject:
+		plan.Injections++
+	case observabilityv1alpha1.PlannedActionUpdate:
+		plan.Updates++
+	case observabilityv1alpha1.PlannedActionStrip:
+		plan.Removals++
+	}
+	plan.PodRestarts += action.PodRestarts
+	plan.CPURequests.Add(action.CPURequests)
+	plan.MemoryRequests.Add(action.MemoryRequests)
+	plan.Actions = append(plan.Actions, action)
+}
+
+// planSummary describes the plan in one sentence, for the Ready condition and the Event
+func planSummary(plan *observabilityv1alpha1.DryRunPlan) string {
+	summary := fmt.Sprintf("Dry run: would inject %d, update %d and strip %d workloads", plan.Injections, plan.Updates, plan.Removals)
+	if plan.Hash != "" {
+		summary += fmt.Sprintf(" to injection hash %s", plan.Hash)
+	}
+	return summary + fmt.Sprintf(", replacing %d running pods and changing requests by %s CPU and %s memory",
+		plan.PodRestarts, plan.CPURequests.String(), plan.MemoryRequests.String())
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// AnnotationInjectionManifest records on the workload what an injection added to its pod template
+const AnnotationInjectionManifest = "vectorsidecar.observability.kontroloop.ai/injection-manifest"
+
+// injectionManifest lists the pod template entries added by an injection, so they can be removed
+// exactly even after the VectorSidecar spec changed or was deleted. Entries the workload defined
+// itself before the injection are never recorded.
+type injectionManifest struct {
+	// Container is the name of the Vector container, in either placement
+	Container string `json:"container"`
+
+	// InitContainers are the names of the injected init containers, excluding a native Vector sidecar
+	InitContainers []string `json:"initContainers,omitempty"`
+
+	// Volumes are the names of the injected volumes
+	Volumes []string `json:"volumes,omitempty"`
+
+	// PodAnnotations are the keys of the injected pod template annotations
+	PodAnnotations []string `json:"podAnnotations,omitempty"`
+
+	// ShareProcessNamespace is set when the batch shutdown signal enabled process namespace sharing
+	ShareProcessNamespace bool `json:"shareProcessNamespace,omitempty"`
+}
+
+// recordedManifest returns the manifest recorded on the workload. Workloads injected before the
+// manifest existed get one derived from the VectorSidecar spec; nil is returned when the
+// workload carries no injection at all.
+func recordedManifest(vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload) *injectionManifest {
+	annotations := wl.GetAnnotations()
+	if value, ok := annotations[AnnotationInjectionManifest]; ok {
+		manifest := &injectionManifest{}
+		if err := json.Unmarshal([]byte(value), manifest); err == nil && manifest.Container != "" {
+			return manifest
+		}
+	}
+	if annotations[AnnotationInjected] != "true" {
+		return nil
+	}
+
+	manifest := &injectionManifest{
+		Container:      sidecarContainerName(vectorSidecar),
+		Volumes:        []string{VectorConfigVolumeName, LifecycleVolumeName},
+		PodAnnotations: append([]string(nil), operatorTemplateAnnotations...),
+	}
+	for _, container := range vectorSidecar.Spec.InitContainers {
+		manifest.InitContainers = append(manifest.InitContainers, container.Name)
+	}
+	for _, volume := range vectorSidecar.Spec.Volumes {
+		manifest.Volumes = append(manifest.Volumes, volume.Name)
+	}
+	return manifest
+}
+
+// newInjectionManifest records what injecting the VectorSidecar adds to the original workload.
+// An init container or volume from the spec that the workload already defines is recorded only
+// when the previous manifest shows it was injected too.
+func newInjectionManifest(vectorSidecar *observabilityv1alpha1.VectorSidecar, original *workload,
+	podSpec *corev1.PodSpec, templateAnnotations map[string]string) *injectionManifest {
+	previous := recordedManifest(vectorSidecar, original)
+	if previous == nil {
+		previous = &injectionManifest{}
+	}
+
+	existingInitContainers := map[string]bool{}
+	for _, container := range original.Template.Spec.InitContainers {
+		existingInitContainers[container.Name] = true
+	}
+	existingVolumes := map[string]bool{}
+	for _, volume := range original.Template.Spec.Volumes {
+		existingVolumes[volume.Name] = true
+	}
+
+	manifest := &injectionManifest{Container: sidecarContainerName(vectorSidecar)}
+	for _, container := range vectorSidecar.Spec.InitContainers {
+		if !existingInitContainers[container.Name] || contains(previous.InitContainers, container.Name) {
+			manifest.InitContainers = append(manifest.InitContainers, container.Name)
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		injected := volume.Name == VectorConfigVolumeName || volume.Name == LifecycleVolumeName
+		for _, specVolume := range vectorSidecar.Spec.Volumes {
+			if specVolume.Name == volume.Name {
+				injected = !existingVolumes[volume.Name] || contains(previous.Volumes, volume.Name)
+				break
+			}
+		}
+		if injected && !contains(manifest.Volumes, volume.Name) {
+			manifest.Volumes = append(manifest.Volumes, volume.Name)
+		}
+	}
+	for key := range templateAnnotations {
+		manifest.PodAnnotations = append(manifest.PodAnnotations, key)
+	}
+	sort.Strings(manifest.PodAnnotations)
+
+	// Process namespace sharing comes with the shutdown signal, unless the workload enabled it itself
+	sharedBefore := original.Template.Spec.ShareProcessNamespace != nil && *original.Template.Spec.ShareProcessNamespace
+	if contains(manifest.Volumes, LifecycleVolumeName) && podSpec.ShareProcessNamespace != nil && *podSpec.ShareProcessNamespace {
+		manifest.ShareProcessNamespace = !sharedBefore || previous.ShareProcessNamespace
+	}
+	return manifest
+}
+
+// conflictingContainer returns the name of a container of the pod spec that carries the name of
+// the Vector container or of an init container from spec.initContainers without having been
+// injected, or an empty string. Injecting would replace such an application container.
+func conflictingContainer(vectorSidecar *observabilityv1alpha1.VectorSidecar, podSpec *corev1.PodSpec, injected *injectionManifest) string {
+	if injected == nil {
+		injected = &injectionManifest{}
+	}
+	names := map[string]bool{sidecarContainerName(vectorSidecar): true}
+	for _, container := range vectorSidecar.Spec.InitContainers {
+		names[container.Name] = true
+	}
+
+	for _, containers := range [][]corev1.Container{podSpec.Containers, podSpec.InitContainers} {
+		for _, container := range containers {
+			if names[container.Name] && container.Name != injected.Container && !contains(injected.InitContainers, container.Name) {
+				return container.Name
+			}
+		}
+	}
+	return ""
+}
+
+// encode returns the manifest as the value of the manifest annotation
+func (m *injectionManifest) encode() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode injection manifest: %w", err)
+	}
+	return string(data), nil
+}
+
+// without returns the entries of the manifest that other does not list
+func (m *injectionManifest) without(other *injectionManifest) *injectionManifest {
+	stale := &injectionManifest{}
+	if m.Container != other.Container {
+		stale.Container = m.Container
+	}
+	for _, name := range m.InitContainers {
+		if !contains(other.InitContainers, name) {
+			stale.InitContainers = append(stale.InitContainers, name)
+		}
+	}
+	for _, name := range m.Volumes {
+		if !contains(other.Volumes, name) {
+			stale.Volumes = append(stale.Volumes, name)
+		}
+	}
+	for _, key := range m.PodAnnotations {
+		if !contains(other.PodAnnotations, key) {
+			stale.PodAnnotations = append(stale.PodAnnotations, key)
+		}
+	}
+	stale.ShareProcessNamespace = m.ShareProcessNamespace && !other.ShareProcessNamespace
+	return stale
+}
+
+// strip removes every entry of the manifest from the pod template
+func (m *injectionManifest) strip(template *corev1.PodTemplateSpec) {
+	podSpec := &template.Spec
+
+	containers := []corev1.Container{}
+	for _, container := range podSpec.Containers {
+		if m.Container == "" || container.Name != m.Container {
+			containers = append(containers, container)
+		}
+	}
+	podSpec.Containers = containers
+
+	initContainers := []corev1.Container{}
+	for _, container := range podSpec.InitContainers {
+		if (m.Container == "" || container.Name != m.Container) && !contains(m.InitContainers, container.Name) {
+			initContainers = append(initContainers, container)
+		}
+	}
+	podSpec.InitContainers = initContainers
+
+	volumes := []corev1.Volume{}
+	for _, volume := range podSpec.Volumes {
+		if !contains(m.Volumes, volume.Name) {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = volumes
+
+	for _, key := range m.PodAnnotations {
+		delete(template.Annotations, key)
+	}
+
+	if m.ShareProcessNamespace {
+		podSpec.ShareProcessNamespace = nil
+	}
+}
+
+// contains reports whether names includes name
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// Values of the result label of vectorsidecar_injections_total
+const (
+	injectionResultSuccess = "success"
+	injectionResultFailure = "failure"
+)
+
+// Values of the action label of vectorsidecar_orphans_total
+const (
+	orphanActionStripped = "stripped"
+	orphanActionAdopted  = "adopted"
+	orphanActionFailed   = "failed"
+)
+
+var (
+	// targetsGauge counts the targets of each VectorSidecar by phase
+	targetsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vectorsidecar_targets",
+		Help: "Number of workloads targeted by a VectorSidecar, by injection phase",
+	}, []string{"namespace", "vectorsidecar", "phase"})
+
+	// injectionsTotal counts the workload writes made to inject or update the sidecar
+	injectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vectorsidecar_injections_total",
+		Help: "Number of sidecar injections and updates written to workloads, by result",
+	}, []string{"result"})
+
+	// removalsTotal counts the workloads the sidecar was removed from
+	removalsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vectorsidecar_removals_total",
+		Help: "Number of workloads the sidecar was removed from",
+	})
+
+	// configValidationFailuresTotal counts the reconciles rejecting the Vector configuration
+	configValidationFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vectorsidecar_config_validation_failures_total",
+		Help: "Number of reconciles that found an invalid Vector configuration",
+	})
+
+	// driftDetectedTotal counts the injected workloads found changed outside the operator
+	driftDetectedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vectorsidecar_drift_detected_total",
+		Help: "Number of injected workloads whose sidecar was changed or removed outside the operator",
+	})
+
+	// orphansTotal counts the workloads found annotated with a deleted VectorSidecar
+	orphansTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vectorsidecar_orphans_total",
+		Help: "Number of workloads injected by a deleted VectorSidecar, by the action taken",
+	}, []string{"action"})
+
+	// reconcileDuration observes the reconcile time of each VectorSidecar and ClusterVectorSidecar.
+	// ClusterVectorSidecars report an empty namespace.
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vectorsidecar_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile a VectorSidecar or ClusterVectorSidecar",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"namespace", "vectorsidecar"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		targetsGauge,
+		injectionsTotal,
+		removalsTotal,
+		configValidationFailuresTotal,
+		driftDetectedTotal,
+		orphansTotal,
+		reconcileDuration,
+	)
+}
+
+// targetPhases lists every phase reported by vectorsidecar_targets, so absent phases read zero
+var targetPhases = []observabilityv1alpha1.TargetPhase{
+	observabilityv1alpha1.TargetPhasePending,
+	observabilityv1alpha1.TargetPhaseInjected,
+	observabilityv1alpha1.TargetPhaseFailed,
+	observabilityv1alpha1.TargetPhaseUnmatched,
+	observabilityv1alpha1.TargetPhaseRemoved,
+}
+
+// recordTargets exports the number of targets of the VectorSidecar in each phase
+func recordTargets(vectorSidecar *observabilityv1alpha1.VectorSidecar) {
+	counts := map[observabilityv1alpha1.TargetPhase]int{}
+	for _, target := range vectorSidecar.Status.Targets {
+		counts[target.Phase]++
+	}
+	for _, phase := range targetPhases {
+		targetsGauge.WithLabelValues(vectorSidecar.Namespace, vectorSidecar.Name, string(phase)).Set(float64(counts[phase]))
+	}
+}
+
+// observeReconcileDuration records the time since start for the named resource
+func observeReconcileDuration(namespace, name string, start time.Time) {
+	reconcileDuration.WithLabelValues(namespace, name).Observe(time.Since(start).Seconds())
+}
+
+// forgetMetrics drops the series of a deleted resource
+func forgetMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "vectorsidecar": name}
+	targetsGauge.DeletePartialMatch(labels)
+	reconcileDuration.DeletePartialMatch(labels)
+}
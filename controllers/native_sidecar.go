@@ -0,0 +1,133 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// nativeSidecarMinVersion is the first Kubernetes release with the SidecarContainers feature enabled by default
+var nativeSidecarMinVersion = version.MustParseGeneric("1.29.0")
+
+// nativeSidecarsSupported reports whether the API server accepts init containers with restartPolicy Always.
+// A successful detection is cached. Detection failures are returned rather than treated as
+// unsupported, so a transient discovery error never moves Vector between placements.
+func (r *VectorSidecarReconciler) nativeSidecarsSupported() (bool, error) {
+	if r.Discovery == nil {
+		return false, nil
+	}
+
+	r.serverVersionMu.Lock()
+	defer r.serverVersionMu.Unlock()
+
+	if r.nativeSidecars != nil {
+		return *r.nativeSidecars, nil
+	}
+
+	info, err := r.Discovery.ServerVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to detect server version: %w", err)
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse server version %q: %w", info.GitVersion, err)
+	}
+
+	supported := serverVersion.AtLeast(nativeSidecarMinVersion)
+	r.nativeSidecars = &supported
+	return supported, nil
+}
+
+// sidecarMode returns the placement used for the Vector container, falling back to
+// container mode when native sidecars are requested but not supported by the cluster
+func (r *VectorSidecarReconciler) sidecarMode(vectorSidecar *observabilityv1alpha1.VectorSidecar) (observabilityv1alpha1.SidecarMode, error) {
+	if vectorSidecar.Spec.Sidecar.Mode != observabilityv1alpha1.SidecarModeNative {
+		return observabilityv1alpha1.SidecarModeContainer, nil
+	}
+
+	supported, err := r.nativeSidecarsSupported()
+	if err != nil {
+		return "", err
+	}
+	if !supported {
+		return observabilityv1alpha1.SidecarModeContainer, nil
+	}
+	return observabilityv1alpha1.SidecarModeNative, nil
+}
+
+// podSidecarMode returns the placement of the Vector container in a pod. Batch pods use a native
+// sidecar wherever the cluster supports it, whatever the requested mode, because the kubelet then
+// stops Vector once the application exits without relying on a shell in the Vector image.
+func (r *VectorSidecarReconciler) podSidecarMode(vectorSidecar *observabilityv1alpha1.VectorSidecar, batch bool) (observabilityv1alpha1.SidecarMode, error) {
+	if !batch {
+		return r.sidecarMode(vectorSidecar)
+	}
+
+	supported, err := r.nativeSidecarsSupported()
+	if err != nil {
+		return "", err
+	}
+	if !supported {
+		return observabilityv1alpha1.SidecarModeContainer, nil
+	}
+	return observabilityv1alpha1.SidecarModeNative, nil
+}
+
+// setNativeSidecarRestartPolicy adds restartPolicy Always to the named init container in a
+// strategic merge patch. The field is set on the patch because the typed Container of this
+// client version cannot carry it.
+func setNativeSidecarRestartPolicy(patch []byte, podSpecPath []string, containerName string) ([]byte, error) {
+	patchMap := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &patchMap); err != nil {
+		return nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+
+	podSpec := patchMap
+	for _, field := range podSpecPath {
+		next, ok := podSpec[field].(map[string]interface{})
+		if !ok {
+			// The pod spec is unchanged, so the init container already carries its restart policy
+			return patch, nil
+		}
+		podSpec = next
+	}
+
+	initContainers, ok := podSpec["initContainers"].([]interface{})
+	if !ok {
+		return patch, nil
+	}
+	for _, item := range initContainers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Leave patch directives such as $patch: delete untouched
+		if _, directive := container["$patch"]; directive {
+			continue
+		}
+		if container["name"] == containerName {
+			container["restartPolicy"] = "Always"
+		}
+	}
+
+	return json.Marshal(patchMap)
+}
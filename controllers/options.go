@@ -0,0 +1,65 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ControllerOptions tunes how the VectorSidecar and ClusterVectorSidecar controllers work through
+// their queues. Zero values keep the controller-runtime defaults.
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the number of resources reconciled in parallel
+	MaxConcurrentReconciles int
+
+	// BackoffBaseDelay is the delay before retrying a failed reconcile, doubled on every
+	// consecutive failure of the same resource
+	BackoffBaseDelay time.Duration
+
+	// BackoffMaxDelay caps the delay between retries of a failing resource
+	BackoffMaxDelay time.Duration
+}
+
+// controllerOptions returns the controller-runtime options. A custom backoff keeps the overall
+// rate limit of the default rate limiter.
+func (o ControllerOptions) controllerOptions() controller.Options {
+	options := controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+	if o.BackoffBaseDelay > 0 && o.BackoffMaxDelay > 0 {
+		options.RateLimiter = workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(o.BackoffBaseDelay, o.BackoffMaxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+		)
+	}
+	return options
+}
+
+// waitForWorkloadWrite blocks until the global workload write limit allows another write
+func (r *VectorSidecarReconciler) waitForWorkloadWrite(ctx context.Context) error {
+	if r.WorkloadWriteLimiter == nil {
+		return nil
+	}
+	if err := r.WorkloadWriteLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("workload write rate limit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// OrphanPolicy selects what the orphan collector does with a workload whose VectorSidecar is gone
+type OrphanPolicy string
+
+const (
+	// OrphanPolicyStrip removes the sidecar from every orphaned workload
+	OrphanPolicyStrip OrphanPolicy = "strip"
+
+	// OrphanPolicyAdopt hands an orphaned workload to the VectorSidecar that now selects it with
+	// the highest precedence, and strips it when none does
+	OrphanPolicyAdopt OrphanPolicy = "adopt"
+)
+
+// adoptionQueueSize bounds the heirs waiting to be handed to the VectorSidecar controller
+const adoptionQueueSize = 64
+
+// OrphanCollector periodically repairs workloads whose injection names a VectorSidecar or
+// ClusterVectorSidecar that no longer exists, as left behind when its finalizer was removed by
+// hand or the cleanup on deletion partially failed. It sweeps once at startup and then every Interval.
+type OrphanCollector struct {
+	client.Client
+	Recorder record.EventRecorder
+	Injector *VectorSidecarReconciler
+	Interval time.Duration
+	Policy   OrphanPolicy
+}
+
+// Start runs the sweeps until the manager stops
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("orphan-collector")
+	ctx = log.IntoContext(ctx, logger)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.sweep(ctx); err != nil {
+			logger.Error(err, "Orphan sweep failed")
+		}
+	}, c.Interval)
+	return nil
+}
+
+// NeedLeaderElection keeps the sweeps on the leader, like the controllers writing workloads
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// sweep collects every workload annotated with a VectorSidecar missing from its namespace or
+// a ClusterVectorSidecar missing from the cluster. A resource being deleted is left to its finalizer.
+func (c *OrphanCollector) sweep(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	exists := map[string]bool{}
+	var sweepErrors []string
+	for _, kind := range supportedWorkloadKinds {
+		workloads, err := c.Injector.listWorkloads(ctx, kind)
+		if err != nil {
+			return err
+		}
+
+		for _, wl := range workloads {
+			owner := orphanOwner(wl)
+			if owner == nil {
+				continue
+			}
+			description := ownerDescription(owner)
+			found, checked := exists[description]
+			if !checked {
+				found, err = c.ownerExists(ctx, owner)
+				if err != nil {
+					return err
+				}
+				exists[description] = found
+			}
+			if found {
+				continue
+			}
+
+			if err := c.collect(ctx, wl, owner); err != nil {
+				logger.Error(err, "Failed to collect orphaned workload", "namespace", wl.GetNamespace(), "workload", wl.String())
+				orphansTotal.WithLabelValues(orphanActionFailed).Inc()
+				c.Recorder.Event(wl.Object, corev1.EventTypeWarning, "OrphanCleanupFailed",
+					fmt.Sprintf("Failed to clean up the sidecar of deleted %s: %v", description, err))
+				sweepErrors = append(sweepErrors, fmt.Sprintf("%s/%s: %v", wl.GetNamespace(), wl, err))
+			}
+		}
+	}
+
+	if len(sweepErrors) > 0 {
+		return fmt.Errorf("failed to collect orphaned workloads: %s", strings.Join(sweepErrors, "; "))
+	}
+	return nil
+}
+
+// orphanOwner returns a stub of the resource the workload's injection names, or nil when the
+// workload carries no injection. The manifest recorded on the workload says what to strip, so
+// the stub stands in for the deleted resource.
+func orphanOwner(wl *workload) *observabilityv1alpha1.VectorSidecar {
+	annotations := wl.GetAnnotations()
+	if name := annotations[AnnotationVectorSidecarName]; name != "" {
+		return &observabilityv1alpha1.VectorSidecar{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: wl.GetNamespace()},
+		}
+	}
+	if name := annotations[AnnotationClusterVectorSidecarName]; name != "" {
+		return &observabilityv1alpha1.VectorSidecar{
+			TypeMeta:   metav1.TypeMeta{Kind: clusterVectorSidecarKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: wl.GetNamespace()},
+		}
+	}
+	return nil
+}
+
+// ownerDescription names the resource an orphan stub stands in for
+func ownerDescription(owner *observabilityv1alpha1.VectorSidecar) string {
+	if isClusterScoped(owner) {
+		return fmt.Sprintf("ClusterVectorSidecar %s", owner.Name)
+	}
+	return fmt.Sprintf("VectorSidecar %s/%s", owner.Namespace, owner.Name)
+}
+
+// ownerExists reports whether the resource an orphan stub stands in for still exists
+func (c *OrphanCollector) ownerExists(ctx context.Context, owner *observabilityv1alpha1.VectorSidecar) (bool, error) {
+	var err error
+	if isClusterScoped(owner) {
+		err = c.Get(ctx, types.NamespacedName{Name: owner.Name}, &observabilityv1alpha1.ClusterVectorSidecar{})
+	} else {
+		err = c.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: owner.Namespace}, &observabilityv1alpha1.VectorSidecar{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get %s: %w", ownerDescription(owner), err)
+	}
+	return err == nil, nil
+}
+
+// collect adopts or strips one orphaned workload according to the policy. An heir is not written
+// to the workload here: the stale ownership is dropped and the heir is enqueued, so its own
+// Reconcile takes the workload over with its rollout strategy, rollback policy and status.
+func (c *OrphanCollector) collect(ctx context.Context, wl *workload, owner *observabilityv1alpha1.VectorSidecar) error {
+	logger := log.FromContext(ctx)
+	description := ownerDescription(owner)
+
+	if c.Policy == OrphanPolicyAdopt {
+		heir, err := c.Injector.workloadWinner(ctx, wl)
+		if err != nil {
+			return err
+		}
+		if heir != nil && heir.Spec.InjectionStrategy != observabilityv1alpha1.InjectionStrategyPod {
+			if err := c.releaseOwnership(ctx, wl, owner); err != nil {
+				return err
+			}
+			if err := c.Injector.enqueueAdoption(ctx, heir); err != nil {
+				return err
+			}
+			logger.Info("Handed orphaned workload to its heir", "namespace", wl.GetNamespace(), "workload", wl.String(), "vectorSidecar", heir.Name)
+			orphansTotal.WithLabelValues(orphanActionAdopted).Inc()
+			c.Recorder.Event(wl.Object, corev1.EventTypeNormal, "OrphanAdopted",
+				fmt.Sprintf("VectorSidecar %s takes over the sidecar of deleted %s", heir.Name, description))
+			return nil
+		}
+	}
+
+	if err := c.Injector.removeSidecar(ctx, owner, wl); err != nil {
+		return err
+	}
+	logger.Info("Stripped orphaned workload", "namespace", wl.GetNamespace(), "workload", wl.String(), "owner", description)
+	orphansTotal.WithLabelValues(orphanActionStripped).Inc()
+	c.Recorder.Event(wl.Object, corev1.EventTypeNormal, "OrphanStripped",
+		fmt.Sprintf("Removed the sidecar of deleted %s", description))
+	return nil
+}
+
+// releaseOwnership removes the annotation naming the deleted resource. The injection and its
+// recorded manifest stay, so the heir replaces them in place.
+func (c *OrphanCollector) releaseOwnership(ctx context.Context, wl *workload, owner *observabilityv1alpha1.VectorSidecar) error {
+	if err := c.Injector.waitForWorkloadWrite(ctx); err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, ownerAnnotation(owner))
+	if err := c.Patch(ctx, wl.Object, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+		return fmt.Errorf("failed to release %s: %w", wl, err)
+	}
+	return nil
+}
+
+// enqueueAdoption asks the VectorSidecar controller to reconcile the heir of an orphaned workload
+func (r *VectorSidecarReconciler) enqueueAdoption(ctx context.Context, heir *observabilityv1alpha1.VectorSidecar) error {
+	if r.adoptions == nil {
+		return nil
+	}
+	select {
+	case r.adoptions <- event.GenericEvent{Object: heir}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// PodWebhookPath is the path the pod injection webhook is served on
+const PodWebhookPath = "/mutate-v1-pod"
+
+//+kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod.observability.kontroloop.ai,admissionReviewVersions=v1
+
+// podInjector injects the Vector sidecar into pods selected by a VectorSidecar
+// with the pod injection strategy
+type podInjector struct {
+	reconciler *VectorSidecarReconciler
+	decoder    *admission.Decoder
+}
+
+// SetupPodWebhookWithManager registers the pod injection webhook with the Manager's webhook server
+func (r *VectorSidecarReconciler) SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	mgr.GetWebhookServer().Register(PodWebhookPath, &webhook.Admission{
+		Handler: &podInjector{reconciler: r, decoder: decoder},
+	})
+	return nil
+}
+
+// Handle injects the container, volumes and init containers rendered from the
+// matching VectorSidecar into the pod being created
+func (p *podInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	if err := p.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// Pods created by a controller only carry their namespace on the request
+	if pod.Namespace == "" {
+		pod.Namespace = req.Namespace
+	}
+
+	// Pods from workloads injected with the workload strategy already carry the sidecar
+	if _, ok := pod.Annotations[AnnotationInjectedHash]; ok {
+		return admission.Allowed("pod already has the Vector sidecar")
+	}
+
+	vectorSidecar, err := p.reconciler.getMatchingPodVectorSidecar(ctx, pod)
+	if err != nil {
+		logger.Error(err, "Failed to look up VectorSidecars for pod", "namespace", pod.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if vectorSidecar == nil {
+		return admission.Allowed("no VectorSidecar selects this pod")
+	}
+
+	// Never replace a container the pod defines itself
+	if name := conflictingContainer(vectorSidecar, &pod.Spec, nil); name != "" {
+		logger.Info("Pod already defines a container named like an injected one, leaving it alone",
+			"vectorSidecar", vectorSidecar.Name, "container", name)
+		return admission.Allowed(fmt.Sprintf("pod already defines a container named %q", name))
+	}
+
+	injected, err := p.reconciler.injectPod(ctx, vectorSidecar, pod)
+	if err != nil {
+		logger.Error(err, "Failed to inject sidecar into pod", "vectorSidecar", vectorSidecar.Name)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	// Apply the change as a strategic merge patch on the raw pod so fields this client
+	// version does not know about, such as native sidecar restart policies, survive
+	mutated, err := strategicpatch.StrategicMergePatch(req.Object.Raw, injected, &corev1.Pod{})
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to apply pod patch: %w", err))
+	}
+
+	logger.Info("Injected sidecar into pod",
+		"namespace", pod.Namespace,
+		"generateName", pod.GenerateName,
+		"name", pod.Name,
+		"vectorSidecar", vectorSidecar.Name)
+	return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+}
+
+// getMatchingPodVectorSidecar returns the enabled VectorSidecar with the pod injection strategy
+// whose selector matches the pod labels, leaving out those in dry run. When several match, the
+// highest priority wins and ties go to the name that sorts first, the same precedence the
+// reconciler applies to workloads.
+func (r *VectorSidecarReconciler) getMatchingPodVectorSidecar(ctx context.Context, pod *corev1.Pod) (*observabilityv1alpha1.VectorSidecar, error) {
+	vectorSidecars := &observabilityv1alpha1.VectorSidecarList{}
+	if err := r.List(ctx, vectorSidecars, client.InNamespace(pod.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list VectorSidecars: %w", err)
+	}
+
+	sortVectorSidecarsByPrecedence(vectorSidecars.Items)
+
+	for i := range vectorSidecars.Items {
+		vectorSidecar := &vectorSidecars.Items[i]
+		if !vectorSidecar.Spec.Enabled || dryRun(vectorSidecar) ||
+			vectorSidecar.Spec.InjectionStrategy != observabilityv1alpha1.InjectionStrategyPod ||
+			!vectorSidecar.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&vectorSidecar.Spec.Selector)
+		if err != nil {
+			// An invalid selector is reported on the VectorSidecar by the reconciler
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return vectorSidecar, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// injectPod renders the sidecar into a copy of the pod and returns the strategic merge patch
+// that turns the pod into the injected one
+func (r *VectorSidecarReconciler) injectPod(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar, pod *corev1.Pod) ([]byte, error) {
+	configHash, err := r.configContentHash(ctx, vectorSidecar)
+	if err != nil {
+		return nil, err
+	}
+	currentHash, err := r.calculateInjectionHash(vectorSidecar, configHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate injection hash: %w", err)
+	}
+
+	podCopy := pod.DeepCopy()
+
+	// Pods owned by a Job need Vector to exit once the application finishes
+	owner := metav1.GetControllerOf(pod)
+	batch := owner != nil && owner.Kind == "Job"
+
+	nativeSidecar, err := r.injectPodSpec(vectorSidecar, &podCopy.Spec, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if podCopy.Annotations == nil {
+		podCopy.Annotations = make(map[string]string)
+	}
+	podCopy.Annotations[AnnotationInjected] = "true"
+	podCopy.Annotations[AnnotationInjectedHash] = currentHash
+	podCopy.Annotations[AnnotationVectorSidecarName] = vectorSidecar.Name
+	if configHash != "" {
+		podCopy.Annotations[AnnotationConfigHash] = configHash
+	}
+
+	return createPodTemplatePatch(pod, podCopy, []string{"spec"}, nativeSidecar)
+}
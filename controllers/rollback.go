@@ -0,0 +1,320 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+const (
+	// reasonCrashLoopBackOff is the waiting reason of a container the kubelet keeps restarting
+	reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+	// reasonProgressDeadlineExceeded is the reason of the Progressing condition of a Deployment
+	// whose rollout stalled for longer than its progress deadline
+	reasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+// injectionRevision is the injection a reconcile pass applies to the matched workloads
+type injectionRevision struct {
+	// vectorSidecar renders the injection; nil when the sidecar is stripped because no
+	// injection was ever known to be good
+	vectorSidecar *observabilityv1alpha1.VectorSidecar
+
+	// hash is the injection hash of the revision
+	hash string
+
+	// rolledBack explains why the desired injection is not applied; nil when it is
+	rolledBack error
+
+	// requeueAfter is when the health of a new injection needs another look, or zero
+	requeueAfter time.Duration
+}
+
+// reconcileRollback watches the health of the targets carrying a new injection hash and decides
+// which injection this pass applies. While the unhealthy targets stay within the failure budget
+// the desired injection is applied; beyond it the VectorSidecar falls back to its last
+// known-good revision until the spec renders a different injection. A hash becomes known-good
+// once every target rolled it out healthily.
+func (r *VectorSidecarReconciler) reconcileRollback(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	workloads []*workload, desiredHash string) (injectionRevision, error) {
+	logger := log.FromContext(ctx)
+	status := &vectorSidecar.Status
+	desired := injectionRevision{vectorSidecar: vectorSidecar, hash: desiredHash}
+
+	if status.FailedHash != "" && status.FailedHash != desiredHash {
+		logger.Info("Spec moved on from the rolled back injection", "failedHash", status.FailedHash, "hash", desiredHash)
+		status.FailedHash = ""
+	}
+
+	if status.FailedHash == "" {
+		if desiredHash == status.LastKnownGoodHash {
+			setDegradedCondition(vectorSidecar, metav1.ConditionFalse, "TargetsHealthy",
+				fmt.Sprintf("Injection hash %s is known to be good", desiredHash))
+			return desired, nil
+		}
+
+		unhealthy, err := r.unhealthyTargets(ctx, vectorSidecar, workloads, desiredHash)
+		if err != nil {
+			return desired, err
+		}
+		// Target status changes do not trigger a reconcile, so poll until the hash is known-good
+		if len(workloads) > 0 {
+			desired.requeueAfter = rolloutPollInterval
+		}
+		if len(unhealthy) == 0 {
+			setDegradedCondition(vectorSidecar, metav1.ConditionFalse, "TargetsHealthy",
+				fmt.Sprintf("No target is unhealthy under injection hash %s", desiredHash))
+			if err := r.recordKnownGood(ctx, vectorSidecar, workloads, desiredHash); err != nil {
+				return desired, err
+			}
+			if status.LastKnownGoodHash == desiredHash {
+				desired.requeueAfter = 0
+			}
+			return desired, nil
+		}
+
+		message := fmt.Sprintf("Injection hash %s left %d/%d workloads unhealthy: %s",
+			desiredHash, len(unhealthy), len(workloads), strings.Join(unhealthy, ", "))
+		policy := vectorSidecar.Spec.RollbackPolicy
+		if policy == nil || len(unhealthy) <= failureBudget(policy, len(workloads)) {
+			setDegradedCondition(vectorSidecar, metav1.ConditionTrue, "TargetsUnhealthy", message)
+			return desired, nil
+		}
+
+		knownGood, err := r.knownGoodRevision(ctx, vectorSidecar)
+		if err != nil {
+			return desired, err
+		}
+		if knownGood.vectorSidecar != nil && knownGood.hash == desiredHash {
+			// Only the configuration content changed, which lives in the ConfigMap
+			setDegradedCondition(vectorSidecar, metav1.ConditionTrue, "TargetsUnhealthy",
+				message+"; the last known-good injection renders the same pod template")
+			return desired, nil
+		}
+
+		logger.Info("Rolling back unhealthy injection", "hash", desiredHash, "knownGoodHash", status.LastKnownGoodHash)
+		status.FailedHash = desiredHash
+		reverted := "removing the sidecar, no injection was known to be good"
+		if knownGood.vectorSidecar != nil {
+			reverted = fmt.Sprintf("reverted to %s", knownGood.hash)
+		}
+		setDegradedCondition(vectorSidecar, metav1.ConditionTrue, "RolledBack", message+"; "+reverted)
+		r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "RolledBack", message+"; "+reverted)
+	}
+
+	knownGood, err := r.knownGoodRevision(ctx, vectorSidecar)
+	if err != nil {
+		return desired, err
+	}
+	knownGood.rolledBack = fmt.Errorf("injection hash %s was rolled back because it left workloads unhealthy", desiredHash)
+	return knownGood, nil
+}
+
+// unhealthyTargets lists the workloads carrying the hash whose Vector container is in
+// CrashLoopBackOff, or whose Deployment exceeded its progress deadline, with the reason
+func (r *VectorSidecarReconciler) unhealthyTargets(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	workloads []*workload, hash string) ([]string, error) {
+	var unhealthy []string
+	for _, wl := range workloads {
+		if appliedInjectionHash(vectorSidecar, wl) != hash {
+			continue
+		}
+		reason, err := r.workloadHealth(ctx, vectorSidecar, wl, hash)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", wl, reason))
+		}
+	}
+	return unhealthy, nil
+}
+
+// workloadHealth returns why the workload is unhealthy under the hash, or an empty string
+func (r *VectorSidecarReconciler) workloadHealth(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	wl *workload, hash string) (string, error) {
+	if deployment, ok := wl.Object.(*appsv1.Deployment); ok && deployment.Status.ObservedGeneration >= deployment.Generation {
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+				condition.Reason == reasonProgressDeadlineExceeded {
+				return reasonProgressDeadlineExceeded, nil
+			}
+		}
+	}
+
+	selector, err := wl.podSelector()
+	if err != nil {
+		return "", fmt.Errorf("invalid pod selector on %s: %w", wl, err)
+	}
+	pods := &corev1.PodList{}
+	if err := r.podReader().List(ctx, pods, client.InNamespace(wl.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", fmt.Errorf("failed to list pods of %s: %w", wl, err)
+	}
+
+	sidecarName := sidecarContainerName(vectorSidecar)
+	for _, pod := range pods.Items {
+		if pod.Annotations[AnnotationInjectedHash] != hash {
+			continue
+		}
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, containerStatus := range statuses {
+				if containerStatus.Name == sidecarName && containerStatus.State.Waiting != nil &&
+					containerStatus.State.Waiting.Reason == reasonCrashLoopBackOff {
+					return reasonCrashLoopBackOff, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// podReader returns the reader target pods are listed with
+func (r *VectorSidecarReconciler) podReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// failureBudget resolves the failure budget against the number of matched workloads, rounding
+// percentages down
+func failureBudget(policy *observabilityv1alpha1.RollbackPolicy, total int) int {
+	if policy.FailureBudget == nil {
+		return 0
+	}
+	budget, err := intstr.GetScaledValueFromIntOrPercent(policy.FailureBudget, total, false)
+	if err != nil || budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// knownGoodRevisionName returns the name of the ControllerRevision holding a known-good injection
+func knownGoodRevisionName(vectorSidecar *observabilityv1alpha1.VectorSidecar, hash string) string {
+	return fmt.Sprintf("%s-%s", vectorSidecar.Name, hash)
+}
+
+// recordKnownGood stores the spec as the known-good revision once every target carries the hash
+// and finished rolling it out. The revision replaces the previous one.
+func (r *VectorSidecarReconciler) recordKnownGood(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	workloads []*workload, hash string) error {
+	if len(workloads) == 0 || hash == vectorSidecar.Status.LastKnownGoodHash {
+		return nil
+	}
+	for _, wl := range workloads {
+		if appliedInjectionHash(vectorSidecar, wl) != hash || !wl.rolloutComplete() {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(vectorSidecar.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode known-good revision: %w", err)
+	}
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        knownGoodRevisionName(vectorSidecar, hash),
+			Namespace:   vectorSidecar.Namespace,
+			Annotations: map[string]string{AnnotationVectorSidecarName: vectorSidecar.Name},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: vectorSidecar.Generation,
+	}
+	if err := controllerutil.SetControllerReference(vectorSidecar, revision, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, revision); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to record known-good revision: %w", err)
+	}
+
+	if previous := vectorSidecar.Status.LastKnownGoodHash; previous != "" {
+		stale := &appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{
+			Name:      knownGoodRevisionName(vectorSidecar, previous),
+			Namespace: vectorSidecar.Namespace,
+		}}
+		if err := r.Delete(ctx, stale); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale known-good revision: %w", err)
+		}
+	}
+	vectorSidecar.Status.LastKnownGoodHash = hash
+	return nil
+}
+
+// knownGoodRevision renders the last known-good revision: the recorded Vector container, init
+// containers and volumes, with the configuration source of the current spec. The revision has
+// no VectorSidecar when none was recorded.
+func (r *VectorSidecarReconciler) knownGoodRevision(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (injectionRevision, error) {
+	hash := vectorSidecar.Status.LastKnownGoodHash
+	if hash == "" {
+		return injectionRevision{}, nil
+	}
+
+	revision := &appsv1.ControllerRevision{}
+	key := types.NamespacedName{Name: knownGoodRevisionName(vectorSidecar, hash), Namespace: vectorSidecar.Namespace}
+	if err := r.Get(ctx, key, revision); err != nil {
+		if apierrors.IsNotFound(err) {
+			return injectionRevision{}, nil
+		}
+		return injectionRevision{}, fmt.Errorf("failed to get known-good revision: %w", err)
+	}
+	spec := observabilityv1alpha1.VectorSidecarSpec{}
+	if err := json.Unmarshal(revision.Data.Raw, &spec); err != nil {
+		return injectionRevision{}, fmt.Errorf("failed to decode known-good revision: %w", err)
+	}
+
+	knownGood := vectorSidecar.DeepCopy()
+	knownGood.Spec.Sidecar = spec.Sidecar
+	knownGood.Spec.Sidecar.Config = vectorSidecar.Spec.Sidecar.Config
+	knownGood.Spec.InitContainers = spec.InitContainers
+	knownGood.Spec.Volumes = spec.Volumes
+
+	knownGoodHash, err := r.desiredInjectionHash(ctx, knownGood)
+	if err != nil {
+		return injectionRevision{}, err
+	}
+	return injectionRevision{vectorSidecar: knownGood, hash: knownGoodHash}, nil
+}
+
+// setDegradedCondition reports the health of the targets under the injection
+func setDegradedCondition(vectorSidecar *observabilityv1alpha1.VectorSidecar, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vectorSidecar.Status.Conditions, metav1.Condition{
+		Type:               observabilityv1alpha1.ConditionTypeDegraded,
+		Status:             status,
+		ObservedGeneration: vectorSidecar.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// rolloutPollInterval is how often a batched rollout checks whether the current batch is available
+const rolloutPollInterval = 15 * time.Second
+
+// rolloutPlan is the part of a batched rollout a reconcile pass may perform
+type rolloutPlan struct {
+	// update holds the workloads, as Kind/name, that may move to the desired hash in this pass
+	update map[string]bool
+
+	// requeueAfter is when the rollout needs another look, or zero once it is complete
+	requeueAfter time.Duration
+}
+
+// allows reports whether the workload may be updated in this pass
+func (p rolloutPlan) allows(wl *workload) bool {
+	return p.update == nil || p.update[wl.String()]
+}
+
+// planRollout decides which of the workloads the VectorSidecar injects move to the desired hash
+// in this pass and records the progress in status.rollout. Without a rollout strategy every
+// workload is updated at once. Otherwise the next batch starts only once every workload of the
+// current batch carries the desired hash and rolled out, and the pause after it elapsed.
+func planRollout(vectorSidecar *observabilityv1alpha1.VectorSidecar, workloads []*workload, desiredHash string, now time.Time) rolloutPlan {
+	strategy := vectorSidecar.Spec.RolloutStrategy
+	if strategy == nil {
+		vectorSidecar.Status.Rollout = nil
+		meta.RemoveStatusCondition(&vectorSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeProgressing)
+		return rolloutPlan{}
+	}
+
+	rollout := vectorSidecar.Status.Rollout
+	if rollout == nil {
+		rollout = &observabilityv1alpha1.RolloutStatus{}
+		vectorSidecar.Status.Rollout = rollout
+	}
+	if rollout.Hash != desiredHash {
+		rollout.Hash = desiredHash
+		rollout.LastBatchCompletionTime = nil
+	}
+
+	byName := map[string]*workload{}
+	var outdated []*workload
+	var updated int32
+	for _, wl := range workloads {
+		byName[wl.String()] = wl
+		if appliedInjectionHash(vectorSidecar, wl) != desiredHash {
+			outdated = append(outdated, wl)
+		} else if wl.rolloutComplete() {
+			updated++
+		}
+	}
+	rollout.TotalTargets = int32(len(workloads))
+	rollout.UpdatedTargets = updated
+
+	// Wait for the workloads of the current batch that are still selected. A workload whose
+	// injection failed keeps the batch open and is retried until it carries the desired hash.
+	plan := rolloutPlan{update: map[string]bool{}, requeueAfter: rolloutPollInterval}
+	var inFlight []string
+	for _, name := range rollout.CurrentBatch {
+		wl, ok := byName[name]
+		if ok && (appliedInjectionHash(vectorSidecar, wl) != desiredHash || !wl.rolloutComplete()) {
+			inFlight = append(inFlight, name)
+			plan.update[name] = true
+		}
+	}
+	if len(inFlight) > 0 {
+		rollout.CurrentBatch = inFlight
+		setProgressingCondition(vectorSidecar, "BatchInProgress",
+			fmt.Sprintf("Waiting for %s to roll out; %d/%d workloads updated", strings.Join(inFlight, ", "), updated, len(workloads)))
+		return plan
+	}
+	if len(rollout.CurrentBatch) > 0 {
+		rollout.CurrentBatch = nil
+		completed := metav1.NewTime(now)
+		rollout.LastBatchCompletionTime = &completed
+	}
+
+	if len(outdated) == 0 {
+		meta.SetStatusCondition(&vectorSidecar.Status.Conditions, metav1.Condition{
+			Type:               observabilityv1alpha1.ConditionTypeProgressing,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: vectorSidecar.Generation,
+			Reason:             "RolloutComplete",
+			Message:            fmt.Sprintf("All %d workloads are updated", len(workloads)),
+		})
+		return rolloutPlan{update: map[string]bool{}}
+	}
+
+	if pause := strategy.PauseBetweenBatches; pause != nil && rollout.LastBatchCompletionTime != nil {
+		next := rollout.LastBatchCompletionTime.Add(pause.Duration)
+		if remaining := next.Sub(now); remaining > 0 {
+			// The message names the start time rather than a countdown, so waiting passes leave status alone
+			setProgressingCondition(vectorSidecar, "BatchPaused",
+				fmt.Sprintf("Next batch starts at %s; %d/%d workloads updated", next.UTC().Format(time.RFC3339), updated, len(workloads)))
+			return rolloutPlan{update: map[string]bool{}, requeueAfter: remaining}
+		}
+	}
+
+	sort.Slice(outdated, func(i, j int) bool {
+		if outdated[i].Kind != outdated[j].Kind {
+			return outdated[i].Kind < outdated[j].Kind
+		}
+		return outdated[i].GetName() < outdated[j].GetName()
+	})
+	size := batchSize(strategy, len(workloads))
+	if size > len(outdated) {
+		size = len(outdated)
+	}
+
+	rollout.CurrentBatch = nil
+	for _, wl := range outdated[:size] {
+		plan.update[wl.String()] = true
+		rollout.CurrentBatch = append(rollout.CurrentBatch, wl.String())
+	}
+	setProgressingCondition(vectorSidecar, "BatchStarted",
+		fmt.Sprintf("Updating %s; %d/%d workloads updated", strings.Join(rollout.CurrentBatch, ", "), updated, len(workloads)))
+	return plan
+}
+
+// batchSize resolves the maximum batch size against the number of matched workloads, rounding
+// percentages up and updating at least one workload per batch
+func batchSize(strategy *observabilityv1alpha1.RolloutStrategy, total int) int {
+	maxBatchSize := intstr.FromInt(1)
+	if strategy.MaxBatchSize != nil {
+		maxBatchSize = *strategy.MaxBatchSize
+	}
+	size, err := intstr.GetScaledValueFromIntOrPercent(&maxBatchSize, total, true)
+	if err != nil || size < 1 {
+		return 1
+	}
+	return size
+}
+
+// setProgressingCondition reports a rollout in progress
+func setProgressingCondition(vectorSidecar *observabilityv1alpha1.VectorSidecar, reason, message string) {
+	meta.SetStatusCondition(&vectorSidecar.Status.Conditions, metav1.Condition{
+		Type:               observabilityv1alpha1.ConditionTypeProgressing,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: vectorSidecar.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// patchStatus re-reads latest and lets merge copy the computed status onto it, then patches the
+// status subresource when merge reports a change. The patch carries the resourceVersion it was
+// computed from, and a conflict starts over from a fresh read.
+func patchStatus(ctx context.Context, c client.Client, latest client.Object, merge func() bool) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(latest), latest); err != nil {
+			return err
+		}
+		base := latest.DeepCopyObject().(client.Object)
+		if !merge() {
+			return nil
+		}
+		return c.Status().Patch(ctx, latest, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// writeStatus stores the status computed for the VectorSidecar when it differs semantically from
+// the stored one. LastUpdateTime is only stamped on an actual change, so a resync that changes
+// nothing writes nothing.
+func (r *VectorSidecarReconciler) writeStatus(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) error {
+	status := vectorSidecar.Status.DeepCopy()
+	latest := &observabilityv1alpha1.VectorSidecar{
+		ObjectMeta: metav1.ObjectMeta{Name: vectorSidecar.Name, Namespace: vectorSidecar.Namespace},
+	}
+	return patchStatus(ctx, r.Client, latest, func() bool {
+		status.LastUpdateTime = latest.Status.LastUpdateTime
+		if equality.Semantic.DeepEqual(latest.Status, *status) {
+			return false
+		}
+		status.LastUpdateTime = metav1.Now()
+		latest.Status = *status
+		return true
+	})
+}
+
+// writeStatus stores the status computed for the ClusterVectorSidecar when it differs
+// semantically from the stored one, stamping LastUpdateTime only on an actual change
+func (r *ClusterVectorSidecarReconciler) writeStatus(ctx context.Context, clusterSidecar *observabilityv1alpha1.ClusterVectorSidecar) error {
+	status := clusterSidecar.Status.DeepCopy()
+	latest := &observabilityv1alpha1.ClusterVectorSidecar{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterSidecar.Name},
+	}
+	return patchStatus(ctx, r.Client, latest, func() bool {
+		status.LastUpdateTime = latest.Status.LastUpdateTime
+		if equality.Semantic.DeepEqual(latest.Status, *status) {
+			return false
+		}
+		status.LastUpdateTime = metav1.Now()
+		latest.Status = *status
+		return true
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// desiredInjectionHash returns the injection hash the VectorSidecar's workloads should carry
+func (r *VectorSidecarReconciler) desiredInjectionHash(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (string, error) {
+	configHash, err := r.configContentHash(ctx, vectorSidecar)
+	if err != nil {
+		return "", err
+	}
+	return r.calculateInjectionHash(vectorSidecar, configHash)
+}
+
+// appliedInjectionHash returns the injection hash the VectorSidecar left on the workload's pod
+// template, or an empty string when the workload is not injected by it
+func appliedInjectionHash(vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload) string {
+	if wl.GetAnnotations()[ownerAnnotation(vectorSidecar)] != vectorSidecar.Name {
+		return ""
+	}
+	return wl.Template.Annotations[AnnotationInjectedHash]
+}
+
+// targetStatus builds the status entry of a workload. The transition time of the previous
+// entry is kept while the phase holds.
+func targetStatus(previous []observabilityv1alpha1.TargetStatus, wl *workload, phase observabilityv1alpha1.TargetPhase,
+	appliedHash, desiredHash string, err error) observabilityv1alpha1.TargetStatus {
+	target := observabilityv1alpha1.TargetStatus{
+		Kind:               wl.Kind,
+		Name:               wl.GetName(),
+		AppliedHash:        appliedHash,
+		DesiredHash:        desiredHash,
+		Phase:              phase,
+		LastTransitionTime: metav1.Now(),
+	}
+	if err != nil {
+		target.LastError = err.Error()
+	}
+
+	for _, prev := range previous {
+		if prev.Kind == target.Kind && prev.Name == target.Name && prev.Phase == target.Phase {
+			target.LastTransitionTime = prev.LastTransitionTime
+			break
+		}
+	}
+	return target
+}
+
+// setTargets stores the targets sorted by kind and name, counts the selected ones still waiting
+// for the desired hash and exports the count of each phase
+func setTargets(vectorSidecar *observabilityv1alpha1.VectorSidecar, targets []observabilityv1alpha1.TargetStatus) {
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Kind != targets[j].Kind {
+			return targets[i].Kind < targets[j].Kind
+		}
+		return targets[i].Name < targets[j].Name
+	})
+
+	var outOfDate int32
+	for _, target := range targets {
+		if target.Phase != observabilityv1alpha1.TargetPhaseRemoved && target.Phase != observabilityv1alpha1.TargetPhaseUnmatched &&
+			target.AppliedHash != target.DesiredHash {
+			outOfDate++
+		}
+	}
+
+	vectorSidecar.Status.Targets = targets
+	vectorSidecar.Status.OutOfDateTargets = outOfDate
+	recordTargets(vectorSidecar)
+}
+
+// removedTargets returns the previous Removed entries that current does not report again
+func removedTargets(previous, current []observabilityv1alpha1.TargetStatus) []observabilityv1alpha1.TargetStatus {
+	var removed []observabilityv1alpha1.TargetStatus
+	for _, prev := range previous {
+		if prev.Phase != observabilityv1alpha1.TargetPhaseRemoved {
+			continue
+		}
+		reported := false
+		for _, target := range current {
+			if target.Kind == prev.Kind && target.Name == prev.Name {
+				reported = true
+				break
+			}
+		}
+		if !reported {
+			removed = append(removed, prev)
+		}
+	}
+	return removed
+}
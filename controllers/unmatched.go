@@ -0,0 +1,135 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// defaultUnmatchedGracePeriod applies when the VectorSidecar leaves unmatchedGracePeriod unset
+const defaultUnmatchedGracePeriod = 5 * time.Minute
+
+// unmatchedCleanup is the outcome of stripping the workloads that stopped matching
+type unmatchedCleanup struct {
+	// targets reports every injected workload that no longer matches
+	targets []observabilityv1alpha1.TargetStatus
+
+	// errors lists the workloads the sidecar could not be stripped from
+	errors []string
+
+	// requeueAfter is when the next grace period elapses, or zero when none is running
+	requeueAfter time.Duration
+}
+
+// unmatchedGracePeriod returns how long an injected workload may stop matching before it is stripped
+func unmatchedGracePeriod(vectorSidecar *observabilityv1alpha1.VectorSidecar) time.Duration {
+	if period := vectorSidecar.Spec.UnmatchedGracePeriod; period != nil {
+		return period.Duration
+	}
+	return defaultUnmatchedGracePeriod
+}
+
+// unmatchedWorkloads returns the workloads of any supported kind annotated with this VectorSidecar
+// that are missing from matched, because their labels, the selector or the target kinds changed
+func (r *VectorSidecarReconciler) unmatchedWorkloads(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	matched []*workload) ([]*workload, error) {
+	injectedWorkloads, err := r.getInjectedWorkloads(ctx, vectorSidecar)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, wl := range matched {
+		selected[wl.String()] = true
+	}
+	var unmatched []*workload
+	for _, wl := range injectedWorkloads {
+		if !selected[wl.String()] {
+			unmatched = append(unmatched, wl)
+		}
+	}
+	return unmatched, nil
+}
+
+// reconcileUnmatched strips the sidecar from the workloads the VectorSidecar injected that it no
+// longer matches. A workload is reported as Unmatched with a Warning Event first and stripped once
+// it stayed unmatched for the grace period, so a selector typo can be fixed before a whole
+// namespace loses its sidecars. The Events are recorded on owner, which is the ClusterVectorSidecar
+// itself for its namespace views.
+func (r *VectorSidecarReconciler) reconcileUnmatched(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar,
+	owner runtime.Object, matched []*workload, now time.Time) (unmatchedCleanup, error) {
+	logger := log.FromContext(ctx)
+
+	unmatched, err := r.unmatchedWorkloads(ctx, vectorSidecar, matched)
+	if err != nil {
+		return unmatchedCleanup{}, err
+	}
+
+	gracePeriod := unmatchedGracePeriod(vectorSidecar)
+	cleanup := unmatchedCleanup{}
+	for _, wl := range unmatched {
+		appliedHash := appliedInjectionHash(vectorSidecar, wl)
+		description := wl.String()
+		if isClusterScoped(vectorSidecar) {
+			description = fmt.Sprintf("%s/%s", wl.GetNamespace(), wl)
+		}
+
+		// The grace period runs from the pass that first reported the workload as unmatched
+		since, reported := now, false
+		for _, prev := range vectorSidecar.Status.Targets {
+			if prev.Kind == wl.Kind && prev.Name == wl.GetName() && prev.Phase == observabilityv1alpha1.TargetPhaseUnmatched {
+				since, reported = prev.LastTransitionTime.Time, true
+				break
+			}
+		}
+
+		if remaining := since.Add(gracePeriod).Sub(now); remaining > 0 {
+			if !reported {
+				r.Recorder.Event(owner, corev1.EventTypeWarning, "WorkloadUnmatched",
+					fmt.Sprintf("%s no longer matches the selector, removing the sidecar in %s", description, gracePeriod))
+			}
+			cleanup.targets = append(cleanup.targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseUnmatched, appliedHash, "", nil))
+			if cleanup.requeueAfter == 0 || remaining < cleanup.requeueAfter {
+				cleanup.requeueAfter = remaining
+			}
+			continue
+		}
+
+		if err := r.removeSidecar(ctx, vectorSidecar, wl); err != nil {
+			// Stay Unmatched so the next pass retries without restarting the grace period
+			logger.Error(err, "Failed to remove sidecar from unmatched workload", "workload", wl.String())
+			cleanup.errors = append(cleanup.errors, fmt.Sprintf("%s: %v", description, err))
+			cleanup.targets = append(cleanup.targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseUnmatched, appliedHash, "", err))
+			continue
+		}
+		r.Recorder.Event(owner, corev1.EventTypeNormal, "SidecarRemoved",
+			fmt.Sprintf("Removed sidecar from %s, which no longer matches the selector", description))
+		cleanup.targets = append(cleanup.targets, targetStatus(vectorSidecar.Status.Targets, wl,
+			observabilityv1alpha1.TargetPhaseRemoved, "", "", nil))
+	}
+	return cleanup, nil
+}
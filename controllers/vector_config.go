@@ -0,0 +1,252 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"sigs.k8s.io/yaml"
+)
+
+// vectorConfigFormat is the serialization of a Vector configuration file
+type vectorConfigFormat string
+
+const (
+	vectorConfigYAML vectorConfigFormat = "yaml"
+	vectorConfigTOML vectorConfigFormat = "toml"
+	vectorConfigJSON vectorConfigFormat = "json"
+)
+
+// vectorConfigFormatForKey detects the configuration format from the ConfigMap key extension.
+// Vector picks the parser the same way, so unknown extensions are treated as YAML.
+func vectorConfigFormatForKey(key string) vectorConfigFormat {
+	switch strings.ToLower(filepath.Ext(key)) {
+	case ".toml":
+		return vectorConfigTOML
+	case ".json":
+		return vectorConfigJSON
+	default:
+		return vectorConfigYAML
+	}
+}
+
+// vectorConfigFileName is the file name the configuration is mounted under in the sidecar.
+// It keeps the key's format so Vector parses the file the way it was validated.
+func vectorConfigFileName(key string) string {
+	return "vector." + string(vectorConfigFormatForKey(key))
+}
+
+// vectorComponent is a source, transform or sink of the Vector topology
+type vectorComponent struct {
+	kind   string
+	id     string
+	inputs []string
+}
+
+func (c vectorComponent) String() string {
+	return fmt.Sprintf("%s %q", c.kind, c.id)
+}
+
+// validateVectorConfig parses the configuration and checks that it describes a usable topology:
+// at least one source and one sink, every component has a type, every input resolves to a
+// source or transform, and transforms do not form a cycle
+func validateVectorConfig(key, content string) error {
+	format := vectorConfigFormatForKey(key)
+
+	raw := map[string]interface{}{}
+	var err error
+	switch format {
+	case vectorConfigTOML:
+		_, err = toml.Decode(content, &raw)
+	case vectorConfigJSON:
+		err = json.Unmarshal([]byte(content), &raw)
+	default:
+		err = yaml.Unmarshal([]byte(content), &raw)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s as %s: %w", key, format, err)
+	}
+
+	sources, err := parseVectorComponents(raw, "sources", "source", false)
+	if err != nil {
+		return err
+	}
+	transforms, err := parseVectorComponents(raw, "transforms", "transform", true)
+	if err != nil {
+		return err
+	}
+	sinks, err := parseVectorComponents(raw, "sinks", "sink", true)
+	if err != nil {
+		return err
+	}
+
+	if len(sources) == 0 {
+		return fmt.Errorf("configuration defines no sources")
+	}
+	if len(sinks) == 0 {
+		return fmt.Errorf("configuration defines no sinks")
+	}
+
+	// Inputs can only point at components that produce events
+	producers := map[string]vectorComponent{}
+	for _, component := range append(append([]vectorComponent{}, sources...), transforms...) {
+		if existing, ok := producers[component.id]; ok {
+			return fmt.Errorf("%s reuses the id of %s", component, existing)
+		}
+		producers[component.id] = component
+	}
+	for _, sink := range sinks {
+		if existing, ok := producers[sink.id]; ok {
+			return fmt.Errorf("%s reuses the id of %s", sink, existing)
+		}
+	}
+
+	// Resolve inputs into the transform graph used for cycle detection
+	edges := map[string][]string{}
+	for _, component := range append(append([]vectorComponent{}, transforms...), sinks...) {
+		for _, input := range component.inputs {
+			resolved := resolveVectorInput(input, producers)
+			if len(resolved) == 0 {
+				return fmt.Errorf("%s has input %q that does not match any source or transform", component, input)
+			}
+			if component.kind == "transform" {
+				edges[component.id] = append(edges[component.id], resolved...)
+			}
+		}
+	}
+
+	return findVectorCycle(transforms, edges)
+}
+
+// parseVectorComponents reads one component table of the configuration in a stable order
+func parseVectorComponents(raw map[string]interface{}, section, kind string, needsInputs bool) ([]vectorComponent, error) {
+	value, ok := raw[section]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	table, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map of component ids to components", section)
+	}
+
+	ids := make([]string, 0, len(table))
+	for id := range table {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	components := make([]vectorComponent, 0, len(ids))
+	for _, id := range ids {
+		component := vectorComponent{kind: kind, id: id}
+
+		fields, ok := table[id].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be a map", component)
+		}
+		if componentType, _ := fields["type"].(string); componentType == "" {
+			return nil, fmt.Errorf("%s has no type", component)
+		}
+
+		if needsInputs {
+			inputs, ok := fields["inputs"].([]interface{})
+			if !ok || len(inputs) == 0 {
+				return nil, fmt.Errorf("%s has no inputs", component)
+			}
+			for _, input := range inputs {
+				name, ok := input.(string)
+				if !ok || name == "" {
+					return nil, fmt.Errorf("%s has an input that is not a component id", component)
+				}
+				component.inputs = append(component.inputs, name)
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// resolveVectorInput returns the ids of the producers an input refers to. Inputs may be
+// a component id, a named output such as "route.errors", or a wildcard such as "app_*".
+func resolveVectorInput(input string, producers map[string]vectorComponent) []string {
+	if strings.Contains(input, "*") {
+		var matched []string
+		for id := range producers {
+			if ok, _ := path.Match(input, id); ok {
+				matched = append(matched, id)
+			}
+		}
+		sort.Strings(matched)
+		return matched
+	}
+
+	if _, ok := producers[input]; ok {
+		return []string{input}
+	}
+
+	if i := strings.LastIndex(input, "."); i > 0 {
+		if _, ok := producers[input[:i]]; ok {
+			return []string{input[:i]}
+		}
+	}
+
+	return nil
+}
+
+// findVectorCycle reports the first transform that is part of a cycle
+func findVectorCycle(transforms []vectorComponent, edges map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := map[string]int{}
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("transform %q is part of an input cycle", id)
+		case done:
+			return nil
+		}
+
+		state[id] = visiting
+		for _, input := range edges[id] {
+			if err := visit(input); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		return nil
+	}
+
+	for _, transform := range transforms {
+		if err := visit(transform.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,9 +21,9 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -31,11 +31,18 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
 )
@@ -47,6 +54,8 @@ const (
 	AnnotationInjectedHash = "vectorsidecar.observability.kontroloop.ai/injected-hash"
 	// AnnotationVectorSidecarName stores the name of the VectorSidecar CR
 	AnnotationVectorSidecarName = "vectorsidecar.observability.kontroloop.ai/sidecar-name"
+	// AnnotationClusterVectorSidecarName stores the name of the ClusterVectorSidecar CR
+	AnnotationClusterVectorSidecarName = "vectorsidecar.observability.kontroloop.ai/cluster-sidecar-name"
 	// AnnotationConfigMapVersion stores the resourceVersion of the ConfigMap
 	AnnotationConfigMapVersion = "vectorsidecar.observability.kontroloop.ai/configmap-version"
 
@@ -54,7 +63,10 @@ const (
 	FinalizerName = "vectorsidecar.observability.kontroloop.ai/finalizer"
 
 	// Vector config volume name
-	VectorConfigVolumeName = "vector-config"
+	VectorConfigVolumeName = observabilityv1alpha1.VectorConfigVolumeName
+
+	// InlineConfigKey is the key under which inline configuration is stored in the generated ConfigMap
+	InlineConfigKey = "vector.yaml"
 )
 
 // VectorSidecarReconciler reconciles a VectorSidecar object
@@ -62,35 +74,64 @@ type VectorSidecarReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// Discovery detects whether the cluster supports native sidecars.
+	// When nil, native mode falls back to container mode.
+	Discovery discovery.ServerVersionInterface
+
+	// APIReader reads pods straight from the API server, so checking target health does not
+	// cache every pod in the cluster. When nil, pods are read through Client.
+	APIReader client.Reader
+
+	// Options tunes the workers and retry backoff of the controller
+	Options ControllerOptions
+
+	// WorkloadWriteLimiter bounds the rate of writes to workloads across every VectorSidecar and
+	// ClusterVectorSidecar. When nil, writes are not limited.
+	WorkloadWriteLimiter flowcontrol.RateLimiter
+
+	// adoptions carries the VectorSidecars the orphan collector handed a workload to
+	adoptions chan event.GenericEvent
+
+	serverVersionMu sync.Mutex
+	nativeSidecars  *bool
 }
 
 //+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=vectorsidecars,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=vectorsidecars/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=observability.kontroloop.ai,resources=vectorsidecars/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch
-//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile handles the reconciliation loop for VectorSidecar resources
-// It watches VectorSidecar CRs and Deployments, injecting Vector sidecar containers
-// into matching Deployments based on label selectors.
+// It watches VectorSidecar CRs and workloads, injecting Vector sidecar containers
+// into matching workloads based on label selectors.
 func (r *VectorSidecarReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("*** RECONCILE CALLED ***", "name", req.Name, "namespace", req.Namespace)
 	logger.Info("Reconciling VectorSidecar", "name", req.Name, "namespace", req.Namespace)
+	start := time.Now()
 
 	// Fetch the VectorSidecar instance
 	vectorSidecar := &observabilityv1alpha1.VectorSidecar{}
 	if err := r.Get(ctx, req.NamespacedName, vectorSidecar); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("VectorSidecar resource not found, ignoring")
+			forgetMetrics(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get VectorSidecar")
 		return ctrl.Result{}, err
 	}
+	defer observeReconcileDuration(req.Namespace, req.Name, start)
 
 	// Handle deletion with finalizer
 	if !vectorSidecar.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -110,84 +151,219 @@ func (r *VectorSidecarReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// Validate the VectorSidecar configuration
 	if err := r.validateConfig(ctx, vectorSidecar); err != nil {
 		logger.Error(err, "Invalid VectorSidecar configuration")
+		configValidationFailuresTotal.Inc()
 		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeConfigValid,
 			metav1.ConditionFalse, "ValidationFailed", err.Error())
 		r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "ValidationFailed", err.Error())
 
 		// Persist the status change
-		if statusErr := r.Status().Update(ctx, vectorSidecar); statusErr != nil {
+		if statusErr := r.writeStatus(ctx, vectorSidecar); statusErr != nil {
 			logger.Error(statusErr, "Failed to update status after validation failure")
 			return ctrl.Result{}, statusErr
 		}
 
-		return ctrl.Result{RequeueAfter: time.Minute}, nil
+		requeueAfter := resyncInterval(vectorSidecar)
+		if requeueAfter > time.Minute {
+			requeueAfter = time.Minute
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeConfigValid,
 		metav1.ConditionTrue, "ValidationSucceeded", "Configuration is valid")
 
+	// A dry run publishes the planned changes and writes nothing else
+	if dryRun(vectorSidecar) {
+		return r.handleDryRun(ctx, vectorSidecar)
+	}
+	vectorSidecar.Status.Plan = nil
+
+	// Materialize inline configuration into the ConfigMap referenced by the config volume
+	if err := r.reconcileInlineConfigMap(ctx, vectorSidecar); err != nil {
+		logger.Error(err, "Failed to reconcile inline config ConfigMap")
+		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeInlineConfigReady,
+			metav1.ConditionFalse, "ConfigMapSyncFailed", err.Error())
+		r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "InlineConfigFailed", err.Error())
+
+		if statusErr := r.writeStatus(ctx, vectorSidecar); statusErr != nil {
+			logger.Error(statusErr, "Failed to update status after inline config failure")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Report the sidecar placement; a detection failure requeues instead of changing placement
+	if err := r.reconcileSidecarModeCondition(ctx, vectorSidecar); err != nil {
+		logger.Error(err, "Failed to determine sidecar mode")
+		return ctrl.Result{}, err
+	}
+
 	// Handle injection based on enabled flag
 	if !vectorSidecar.Spec.Enabled {
-		logger.Info("VectorSidecar is disabled, removing sidecars from deployments")
+		logger.Info("VectorSidecar is disabled, removing sidecars from workloads")
 		return r.handleDisabledSidecar(ctx, vectorSidecar)
 	}
 
-	// Get matching deployments
-	matchedDeployments, err := r.getMatchingDeployments(ctx, vectorSidecar)
+	// Pods are injected by the admission webhook, so workloads are left untouched
+	if vectorSidecar.Spec.InjectionStrategy == observabilityv1alpha1.InjectionStrategyPod {
+		return r.handlePodInjectionStrategy(ctx, vectorSidecar)
+	}
+
+	// Get matching workloads
+	matchedWorkloads, err := r.getMatchingWorkloads(ctx, vectorSidecar)
 	if err != nil {
-		logger.Error(err, "Failed to get matching deployments")
+		logger.Error(err, "Failed to get matching workloads")
 		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
-			metav1.ConditionFalse, "DeploymentListFailed", err.Error())
+			metav1.ConditionFalse, "WorkloadListFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Found matching workloads", "count", len(matchedWorkloads))
+
+	// Only the VectorSidecar with the highest precedence mutates a workload
+	ownedWorkloads, conflicts, err := r.resolveConflicts(ctx, vectorSidecar, matchedWorkloads)
+	if err != nil {
+		logger.Error(err, "Failed to resolve conflicting VectorSidecars")
+		return ctrl.Result{}, err
+	}
+	for _, conflict := range conflicts {
+		logger.Info("Workload is injected by another VectorSidecar, skipping",
+			"workload", conflict.workload.String(), "winner", conflict.winner)
+	}
+	setConflictCondition(&vectorSidecar.Status.Conditions, vectorSidecar.Generation, conflicts)
+
+	desiredHash, err := r.desiredInjectionHash(ctx, vectorSidecar)
+	if err != nil {
+		logger.Error(err, "Failed to calculate injection hash")
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("Found matching deployments", "count", len(matchedDeployments))
+	// Targets left unhealthy by a new injection fall back to the last known-good one
+	revision, err := r.reconcileRollback(ctx, vectorSidecar, ownedWorkloads, desiredHash)
+	if err != nil {
+		logger.Error(err, "Failed to check the health of the injection")
+		return ctrl.Result{}, err
+	}
+
+	// Only the current batch of a progressive rollout moves to the desired hash, while a
+	// rollback reverts every target at once
+	plan := rolloutPlan{}
+	if revision.rolledBack == nil {
+		plan = planRollout(vectorSidecar, ownedWorkloads, desiredHash, time.Now())
+	} else if vectorSidecar.Spec.RolloutStrategy != nil {
+		meta.SetStatusCondition(&vectorSidecar.Status.Conditions, metav1.Condition{
+			Type:               observabilityv1alpha1.ConditionTypeProgressing,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: vectorSidecar.Generation,
+			Reason:             "RolledBack",
+			Message:            revision.rolledBack.Error(),
+		})
+	}
+	phase := observabilityv1alpha1.TargetPhaseInjected
+	if revision.rolledBack != nil {
+		phase = observabilityv1alpha1.TargetPhaseFailed
+	}
+
+	// Workloads injected earlier that are no longer matched lose the sidecar after a grace period
+	cleanup, err := r.reconcileUnmatched(ctx, vectorSidecar, vectorSidecar, matchedWorkloads, time.Now())
+	if err != nil {
+		logger.Error(err, "Failed to list injected workloads")
+		return ctrl.Result{}, err
+	}
 
-	// Inject sidecar into matching deployments
+	// Inject sidecar into matching workloads
 	injectedCount := 0
 	var injectionErrors []string
+	targets := make([]observabilityv1alpha1.TargetStatus, 0, len(ownedWorkloads))
 
-	for _, deployment := range matchedDeployments {
-		if err := r.injectSidecar(ctx, vectorSidecar, &deployment); err != nil {
-			logger.Error(err, "Failed to inject sidecar", "deployment", deployment.Name)
-			injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", deployment.Name, err))
+	for _, wl := range ownedWorkloads {
+		appliedHash := appliedInjectionHash(vectorSidecar, wl)
+		if appliedHash != revision.hash && !plan.allows(wl) {
+			if appliedHash != "" {
+				injectedCount++
+			}
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhasePending, appliedHash, desiredHash, nil))
+			continue
+		}
+		if revision.vectorSidecar == nil {
+			// No injection was ever known to be good, so the rollback strips the sidecar
+			if appliedHash != "" {
+				if err := r.removeSidecar(ctx, vectorSidecar, wl); err != nil {
+					logger.Error(err, "Failed to roll back sidecar", "workload", wl.String())
+					injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", wl, err))
+					targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+						observabilityv1alpha1.TargetPhaseFailed, appliedHash, desiredHash, err))
+					continue
+				}
+			}
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseFailed, "", desiredHash, revision.rolledBack))
+			continue
+		}
+		if err := r.injectSidecar(ctx, revision.vectorSidecar, wl); err != nil {
+			logger.Error(err, "Failed to inject sidecar", "workload", wl.String())
+			injectionErrors = append(injectionErrors, fmt.Sprintf("%s: %v", wl, err))
 			r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "InjectionFailed",
-				fmt.Sprintf("Failed to inject into %s: %v", deployment.Name, err))
+				fmt.Sprintf("Failed to inject into %s: %v", wl, err))
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseFailed, appliedHash, desiredHash, err))
 		} else {
 			injectedCount++
 			r.Recorder.Event(vectorSidecar, corev1.EventTypeNormal, "InjectionSucceeded",
-				fmt.Sprintf("Successfully injected sidecar into %s", deployment.Name))
+				fmt.Sprintf("Successfully injected sidecar into %s", wl))
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				phase, revision.hash, desiredHash, revision.rolledBack))
 		}
 	}
 
+	targets = append(targets, cleanup.targets...)
+	injectionErrors = append(injectionErrors, cleanup.errors...)
+
+	// Keep reporting workloads stripped on earlier passes until they are selected again
+	targets = append(targets, removedTargets(vectorSidecar.Status.Targets, targets)...)
+
 	// Update status
-	vectorSidecar.Status.MatchedDeployments = int32(len(matchedDeployments))
+	vectorSidecar.Status.MatchedDeployments = int32(len(matchedWorkloads))
 	vectorSidecar.Status.InjectedDeployments = int32(injectedCount)
-	vectorSidecar.Status.LastUpdateTime = metav1.Now()
+	vectorSidecar.Status.InjectedHash = desiredHash
+	setTargets(vectorSidecar, targets)
 	vectorSidecar.Status.ObservedGeneration = vectorSidecar.Generation
 
 	if len(injectionErrors) > 0 {
-		errorMsg := fmt.Sprintf("Injected %d/%d deployments. Errors: %v", injectedCount, len(matchedDeployments), injectionErrors)
+		errorMsg := fmt.Sprintf("Injected %d/%d workloads. Errors: %v", injectedCount, len(matchedWorkloads), injectionErrors)
 		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
 			metav1.ConditionFalse, "InjectionPartiallyFailed", errorMsg)
+	} else if revision.rolledBack != nil {
+		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionFalse, "RolledBack", revision.rolledBack.Error())
 	} else if injectedCount > 0 {
 		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
-			metav1.ConditionTrue, "InjectionSucceeded", fmt.Sprintf("Injected %d deployments", injectedCount))
+			metav1.ConditionTrue, "InjectionSucceeded", fmt.Sprintf("Injected %d workloads", injectedCount))
+	} else if len(conflicts) > 0 {
+		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+			metav1.ConditionTrue, "WorkloadsClaimed",
+			fmt.Sprintf("All %d matching workloads are injected by other VectorSidecars", len(conflicts)))
 	} else {
 		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
-			metav1.ConditionTrue, "NoMatchingDeployments", "No deployments match the selector")
+			metav1.ConditionTrue, "NoMatchingWorkloads", "No workloads match the selector")
 	}
 
-	if err := r.Status().Update(ctx, vectorSidecar); err != nil {
+	if err := r.writeStatus(ctx, vectorSidecar); err != nil {
 		logger.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("Reconciliation complete", "matched", len(matchedDeployments), "injected", injectedCount)
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	logger.Info("Reconciliation complete", "matched", len(matchedWorkloads), "injected", injectedCount)
+	requeueAfter := resyncInterval(vectorSidecar)
+	for _, after := range []time.Duration{plan.requeueAfter, revision.requeueAfter, cleanup.requeueAfter} {
+		if after > 0 && after < requeueAfter {
+			requeueAfter = after
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// handleDeletion removes sidecars from all deployments when VectorSidecar is deleted
+// handleDeletion removes sidecars from all workloads when VectorSidecar is deleted
 func (r *VectorSidecarReconciler) handleDeletion(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Handling VectorSidecar deletion")
@@ -196,25 +372,23 @@ func (r *VectorSidecarReconciler) handleDeletion(ctx context.Context, vectorSide
 		return ctrl.Result{}, nil
 	}
 
-	// Get all deployments with our annotation
-	deployments := &appsv1.DeploymentList{}
-	if err := r.List(ctx, deployments, client.InNamespace(vectorSidecar.Namespace)); err != nil {
-		logger.Error(err, "Failed to list deployments for cleanup")
+	// Get all workloads with our annotation
+	injectedWorkloads, err := r.getInjectedWorkloads(ctx, vectorSidecar)
+	if err != nil {
+		logger.Error(err, "Failed to list workloads for cleanup")
 		return ctrl.Result{}, err
 	}
 
-	// Remove sidecars from deployments that reference this VectorSidecar
+	// Remove sidecars from workloads that reference this VectorSidecar
 	// Track errors but don't fail the deletion - best effort cleanup
 	var cleanupErrors []string
-	for _, deployment := range deployments.Items {
-		if deployment.Annotations[AnnotationVectorSidecarName] == vectorSidecar.Name {
-			if err := r.removeSidecar(ctx, &deployment); err != nil {
-				logger.Error(err, "Failed to remove sidecar during deletion", "deployment", deployment.Name)
-				cleanupErrors = append(cleanupErrors, fmt.Sprintf("%s: %v", deployment.Name, err))
-				// Continue cleanup even if one deployment fails
-			} else {
-				logger.Info("Removed sidecar during cleanup", "deployment", deployment.Name)
-			}
+	for _, wl := range injectedWorkloads {
+		if err := r.removeSidecar(ctx, vectorSidecar, wl); err != nil {
+			logger.Error(err, "Failed to remove sidecar during deletion", "workload", wl.String())
+			cleanupErrors = append(cleanupErrors, fmt.Sprintf("%s: %v", wl, err))
+			// Continue cleanup even if one workload fails
+		} else {
+			logger.Info("Removed sidecar during cleanup", "workload", wl.String())
 		}
 	}
 
@@ -222,7 +396,7 @@ func (r *VectorSidecarReconciler) handleDeletion(ctx context.Context, vectorSide
 	if len(cleanupErrors) > 0 {
 		logger.Info("Some cleanup operations failed, but removing finalizer to allow deletion", "errors", cleanupErrors)
 		r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "PartialCleanup",
-			fmt.Sprintf("Failed to clean up some deployments: %v", cleanupErrors))
+			fmt.Sprintf("Failed to clean up some workloads: %v", cleanupErrors))
 	}
 
 	// Remove finalizer - always try to remove it to prevent stuck resources
@@ -240,32 +414,137 @@ func (r *VectorSidecarReconciler) handleDeletion(ctx context.Context, vectorSide
 func (r *VectorSidecarReconciler) handleDisabledSidecar(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	deployments := &appsv1.DeploymentList{}
-	if err := r.List(ctx, deployments, client.InNamespace(vectorSidecar.Namespace)); err != nil {
+	injectedWorkloads, err := r.getInjectedWorkloads(ctx, vectorSidecar)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	removedCount := 0
-	for _, deployment := range deployments.Items {
-		if deployment.Annotations[AnnotationVectorSidecarName] == vectorSidecar.Name {
-			if err := r.removeSidecar(ctx, &deployment); err != nil {
-				logger.Error(err, "Failed to remove sidecar", "deployment", deployment.Name)
-			} else {
-				removedCount++
+	targets := make([]observabilityv1alpha1.TargetStatus, 0, len(injectedWorkloads))
+	for _, wl := range injectedWorkloads {
+		appliedHash := appliedInjectionHash(vectorSidecar, wl)
+		if err := r.removeSidecar(ctx, vectorSidecar, wl); err != nil {
+			logger.Error(err, "Failed to remove sidecar", "workload", wl.String())
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseFailed, appliedHash, "", err))
+		} else {
+			removedCount++
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseRemoved, "", "", nil))
+		}
+	}
+
+	// Keep reporting workloads stripped on earlier passes until they are selected again
+	targets = append(targets, removedTargets(vectorSidecar.Status.Targets, targets)...)
+
+	vectorSidecar.Status.InjectedDeployments = 0
+	setTargets(vectorSidecar, targets)
+	setConflictCondition(&vectorSidecar.Status.Conditions, vectorSidecar.Generation, nil)
+	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+		metav1.ConditionTrue, "SidecarDisabled", fmt.Sprintf("Removed sidecars from %d workloads", removedCount))
+
+	if err := r.writeStatus(ctx, vectorSidecar); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: resyncInterval(vectorSidecar)}, nil
+}
+
+// handlePodInjectionStrategy removes workload-level injections left over from the workload
+// strategy and reports the workloads whose pods are injected by the webhook
+func (r *VectorSidecarReconciler) handlePodInjectionStrategy(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	injectedWorkloads, err := r.getInjectedWorkloads(ctx, vectorSidecar)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	targets := make([]observabilityv1alpha1.TargetStatus, 0, len(injectedWorkloads))
+	for _, wl := range injectedWorkloads {
+		appliedHash := appliedInjectionHash(vectorSidecar, wl)
+		if err := r.removeSidecar(ctx, vectorSidecar, wl); err != nil {
+			logger.Error(err, "Failed to remove workload-level sidecar", "workload", wl.String())
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseFailed, appliedHash, "", err))
+		} else {
+			logger.Info("Removed workload-level sidecar in favour of pod injection", "workload", wl.String())
+			targets = append(targets, targetStatus(vectorSidecar.Status.Targets, wl,
+				observabilityv1alpha1.TargetPhaseRemoved, "", "", nil))
+		}
+	}
+	targets = append(targets, removedTargets(vectorSidecar.Status.Targets, targets)...)
+
+	// The webhook matches pod labels, so count workloads by their pod template labels
+	selector, err := metav1.LabelSelectorAsSelector(&vectorSidecar.Spec.Selector)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid selector: %w", err)
+	}
+	var matchedWorkloads []*workload
+	for _, kind := range targetKinds(vectorSidecar) {
+		workloads, err := r.listWorkloads(ctx, kind, client.InNamespace(vectorSidecar.Namespace))
+		if err != nil {
+			logger.Error(err, "Failed to get matching workloads")
+			r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
+				metav1.ConditionFalse, "WorkloadListFailed", err.Error())
+			return ctrl.Result{}, err
+		}
+		for _, wl := range workloads {
+			if selector.Matches(labels.Set(wl.Template.Labels)) {
+				matchedWorkloads = append(matchedWorkloads, wl)
 			}
 		}
 	}
 
+	// The webhook skips pods of workloads injected by a VectorSidecar with higher precedence
+	_, conflicts, err := r.resolveConflicts(ctx, vectorSidecar, matchedWorkloads)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	setConflictCondition(&vectorSidecar.Status.Conditions, vectorSidecar.Generation, conflicts)
+
+	vectorSidecar.Status.MatchedDeployments = int32(len(matchedWorkloads))
 	vectorSidecar.Status.InjectedDeployments = 0
-	vectorSidecar.Status.LastUpdateTime = metav1.Now()
+	setTargets(vectorSidecar, targets)
+	vectorSidecar.Status.ObservedGeneration = vectorSidecar.Generation
 	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeReady,
-		metav1.ConditionTrue, "SidecarDisabled", fmt.Sprintf("Removed sidecars from %d deployments", removedCount))
+		metav1.ConditionTrue, "PodInjectionActive", "Sidecars are injected into matching pods at creation time by the admission webhook")
 
-	if err := r.Status().Update(ctx, vectorSidecar); err != nil {
+	if err := r.writeStatus(ctx, vectorSidecar); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	return ctrl.Result{RequeueAfter: resyncInterval(vectorSidecar)}, nil
+}
+
+// reconcileSidecarModeCondition sets the NativeSidecarSupported condition for native mode and
+// emits the fallback warning only when the condition first turns false
+func (r *VectorSidecarReconciler) reconcileSidecarModeCondition(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) error {
+	if vectorSidecar.Spec.Sidecar.Mode != observabilityv1alpha1.SidecarModeNative {
+		meta.RemoveStatusCondition(&vectorSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeNativeSidecarSupported)
+		return nil
+	}
+
+	mode, err := r.sidecarMode(vectorSidecar)
+	if err != nil {
+		return err
+	}
+
+	if mode == observabilityv1alpha1.SidecarModeNative {
+		r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeNativeSidecarSupported,
+			metav1.ConditionTrue, "NativeSidecarsSupported", "Vector is injected as a native sidecar")
+		return nil
+	}
+
+	if !meta.IsStatusConditionFalse(vectorSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeNativeSidecarSupported) {
+		log.FromContext(ctx).Info("Native sidecars are not supported by this cluster, falling back to container mode")
+		r.Recorder.Event(vectorSidecar, corev1.EventTypeWarning, "NativeSidecarUnsupported",
+			"Native sidecars require Kubernetes 1.29 or later, injecting Vector as a regular container")
+	}
+	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeNativeSidecarSupported,
+		metav1.ConditionFalse, "NativeSidecarUnsupported",
+		"Native sidecars require Kubernetes 1.29 or later, Vector is injected as a regular container")
+	return nil
 }
 
 // validateConfig validates the VectorSidecar configuration
@@ -286,120 +565,203 @@ func (r *VectorSidecarReconciler) validateConfig(ctx context.Context, vectorSide
 			return fmt.Errorf("configMap %s not found: %w", cmName.Name, err)
 		}
 
-		key := vectorSidecar.Spec.Sidecar.Config.ConfigMapRef.Key
-		if key == "" {
-			key = "vector.yaml"
-		}
-		if _, ok := cm.Data[key]; !ok {
+		key := configSourceKey(vectorSidecar)
+		content, ok := cm.Data[key]
+		if !ok {
 			return fmt.Errorf("configMap %s does not contain key %s", cmName.Name, key)
 		}
+		if err := validateVectorConfig(key, content); err != nil {
+			return fmt.Errorf("configMap %s key %s: %w", cmName.Name, key, err)
+		}
+		return nil
+	}
+
+	if err := validateVectorConfig(InlineConfigKey, vectorSidecar.Spec.Sidecar.Config.Inline); err != nil {
+		return fmt.Errorf("inline configuration: %w", err)
 	}
 
 	return nil
 }
 
-// getMatchingDeployments returns deployments matching the selector
-func (r *VectorSidecarReconciler) getMatchingDeployments(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) ([]appsv1.Deployment, error) {
-	deploymentList := &appsv1.DeploymentList{}
+// reconcileInlineConfigMap creates, updates or deletes the ConfigMap holding inline configuration
+func (r *VectorSidecarReconciler) reconcileInlineConfigMap(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) error {
+	logger := log.FromContext(ctx)
 
-	// List deployments in the same namespace
-	listOpts := []client.ListOption{
-		client.InNamespace(vectorSidecar.Namespace),
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inlineConfigMapName(vectorSidecar),
+			Namespace: vectorSidecar.Namespace,
+		},
 	}
 
-	if err := r.List(ctx, deploymentList, listOpts...); err != nil {
-		return nil, err
+	// Garbage-collect the ConfigMap when the inline source is no longer used. A configMapRef
+	// takes precedence in injectVolumes, so inline content next to it is never mounted.
+	if vectorSidecar.Spec.Sidecar.Config.Inline == "" || vectorSidecar.Spec.Sidecar.Config.ConfigMapRef != nil {
+		meta.RemoveStatusCondition(&vectorSidecar.Status.Conditions, observabilityv1alpha1.ConditionTypeInlineConfigReady)
+
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(cm, vectorSidecar) {
+			return nil
+		}
+		if err := r.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete inline config ConfigMap %s: %w", cm.Name, err)
+		}
+		logger.Info("Deleted unused inline config ConfigMap", "configMap", cm.Name)
+		return nil
 	}
 
-	// Filter deployments by label selector
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		// Refuse to take over a ConfigMap that was not created for this VectorSidecar
+		if !cm.CreationTimestamp.IsZero() && !metav1.IsControlledBy(cm, vectorSidecar) {
+			return fmt.Errorf("configMap %s already exists and is not owned by VectorSidecar %s", cm.Name, vectorSidecar.Name)
+		}
+		// Object names can exceed the 63 character limit of label values
+		if cm.Annotations == nil {
+			cm.Annotations = make(map[string]string)
+		}
+		cm.Annotations[AnnotationVectorSidecarName] = vectorSidecar.Name
+		cm.Data = map[string]string{
+			InlineConfigKey: vectorSidecar.Spec.Sidecar.Config.Inline,
+		}
+		return controllerutil.SetControllerReference(vectorSidecar, cm, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync inline config ConfigMap %s: %w", cm.Name, err)
+	}
+
+	if op != controllerutil.OperationResultNone {
+		logger.Info("Synced inline config ConfigMap", "configMap", cm.Name, "operation", op)
+	}
+	r.updateStatusCondition(ctx, vectorSidecar, observabilityv1alpha1.ConditionTypeInlineConfigReady,
+		metav1.ConditionTrue, "ConfigMapSynced", fmt.Sprintf("Inline configuration stored in ConfigMap %s", cm.Name))
+	return nil
+}
+
+// inlineConfigMapName returns the name of the ConfigMap generated from inline configuration
+func inlineConfigMapName(vectorSidecar *observabilityv1alpha1.VectorSidecar) string {
+	return fmt.Sprintf("%s-inline-config", vectorSidecar.Name)
+}
+
+// configSourceKey returns the ConfigMap key holding the Vector configuration
+func configSourceKey(vectorSidecar *observabilityv1alpha1.VectorSidecar) string {
+	if ref := vectorSidecar.Spec.Sidecar.Config.ConfigMapRef; ref != nil {
+		if ref.Key == "" {
+			return "vector.yaml"
+		}
+		return ref.Key
+	}
+	return InlineConfigKey
+}
+
+// getMatchingWorkloads returns workloads of the target kinds matching the selector
+func (r *VectorSidecarReconciler) getMatchingWorkloads(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) ([]*workload, error) {
+	// Filter workloads by label selector
 	selector, err := metav1.LabelSelectorAsSelector(&vectorSidecar.Spec.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("invalid selector: %w", err)
 	}
 
-	var matched []appsv1.Deployment
-	for _, deployment := range deploymentList.Items {
-		if selector.Matches(labels.Set(deployment.Labels)) {
-			matched = append(matched, deployment)
+	var matched []*workload
+	for _, kind := range targetKinds(vectorSidecar) {
+		// List matching workloads in the same namespace
+		workloads, err := r.listMatchingWorkloads(ctx, kind, vectorSidecar.Namespace, selector)
+		if err != nil {
+			return nil, err
 		}
+		matched = append(matched, workloads...)
 	}
 
 	return matched, nil
 }
 
-// injectSidecar injects the Vector sidecar into a deployment
-func (r *VectorSidecarReconciler) injectSidecar(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar, deployment *appsv1.Deployment) error {
+// getInjectedWorkloads returns workloads of any supported kind annotated with this VectorSidecar
+func (r *VectorSidecarReconciler) getInjectedWorkloads(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar) ([]*workload, error) {
+	var injected []*workload
+	for _, kind := range supportedWorkloadKinds {
+		workloads, err := r.listWorkloads(ctx, kind, client.InNamespace(vectorSidecar.Namespace),
+			client.MatchingFields{ownerIndexField(vectorSidecar): vectorSidecar.Name})
+		if err != nil {
+			return nil, err
+		}
+		injected = append(injected, workloads...)
+	}
+
+	return injected, nil
+}
+
+// injectSidecar injects the Vector sidecar into a workload
+func (r *VectorSidecarReconciler) injectSidecar(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload) (err error) {
 	logger := log.FromContext(ctx)
+	defer func() {
+		if err != nil {
+			injectionsTotal.WithLabelValues(injectionResultFailure).Inc()
+		}
+	}()
 
-	// Calculate the current injection hash
-	currentHash, err := r.calculateInjectionHash(vectorSidecar)
+	// Calculate the current injection hash, including the configuration content under RestartPods
+	configHash, err := r.configContentHash(ctx, vectorSidecar)
+	if err != nil {
+		return err
+	}
+	currentHash, err := r.calculateInjectionHash(vectorSidecar, configHash)
 	if err != nil {
 		return fmt.Errorf("failed to calculate injection hash: %w", err)
 	}
 
-	// Check if already injected with the same configuration
-	if deployment.Annotations != nil {
-		if existingHash, ok := deployment.Annotations[AnnotationInjectedHash]; ok {
-			if existingHash == currentHash {
-				logger.Info("Deployment already has matching sidecar configuration, skipping",
-					"deployment", deployment.Name, "hash", currentHash)
+	// Check if already injected with the same configuration by the same owner
+	if existingHash, ok := wl.GetAnnotations()[AnnotationInjectedHash]; ok {
+		if existingHash == currentHash && wl.GetAnnotations()[ownerAnnotation(vectorSidecar)] == vectorSidecar.Name {
+			outdated := false
+			if wl.isBatch() {
+				mode, err := r.podSidecarMode(vectorSidecar, true)
+				if err != nil {
+					return err
+				}
+				outdated = shutdownHandlingOutdated(&wl.Template.Spec, sidecarContainerName(vectorSidecar),
+					mode == observabilityv1alpha1.SidecarModeNative)
+			}
+			drifted := injectionDrifted(vectorSidecar, wl, currentHash)
+			if !drifted && !outdated {
+				logger.Info("Workload already has matching sidecar configuration, skipping",
+					"workload", wl.String(), "hash", currentHash)
 				return nil
 			}
-			logger.Info("Sidecar configuration changed, updating deployment",
-				"deployment", deployment.Name,
+			if drifted {
+				r.reportDrift(ctx, vectorSidecar, wl, currentHash)
+			} else {
+				logger.Info("Moving the batch workload to the current shutdown handling",
+					"workload", wl.String(), "hash", currentHash)
+			}
+		} else {
+			logger.Info("Sidecar configuration changed, updating workload",
+				"workload", wl.String(),
 				"oldHash", existingHash,
 				"newHash", currentHash,
 				"newImage", vectorSidecar.Spec.Sidecar.Image)
 		}
 	}
 
-	// Create a copy of the deployment for modification
-	deploymentCopy := deployment.DeepCopy()
-
-	// Ensure annotations map exists
-	if deploymentCopy.Annotations == nil {
-		deploymentCopy.Annotations = make(map[string]string)
-	}
-	if deploymentCopy.Spec.Template.Annotations == nil {
-		deploymentCopy.Spec.Template.Annotations = make(map[string]string)
-	}
-
-	// Remove existing Vector container if present
-	containers := []corev1.Container{}
-	sidecarName := vectorSidecar.Spec.Sidecar.Name
-	if sidecarName == "" {
-		sidecarName = "vector"
-	}
-
-	for _, container := range deploymentCopy.Spec.Template.Spec.Containers {
-		if container.Name != sidecarName {
-			containers = append(containers, container)
-		}
+	// Never replace a container the workload defines itself
+	previous := recordedManifest(vectorSidecar, wl)
+	if name := conflictingContainer(vectorSidecar, &wl.Template.Spec, previous); name != "" {
+		return fmt.Errorf("%s already defines a container named %q that was not injected by the operator", wl, name)
 	}
 
-	// Build the Vector sidecar container
-	vectorContainer := r.buildVectorContainer(vectorSidecar)
-	containers = append(containers, vectorContainer)
-	deploymentCopy.Spec.Template.Spec.Containers = containers
-
-	// Handle volumes
-	if err := r.injectVolumes(vectorSidecar, deploymentCopy); err != nil {
-		return fmt.Errorf("failed to inject volumes: %w", err)
+	// Render the sidecar into a copy of the pod template
+	wlCopy := wl.DeepCopy()
+	nativeSidecar, err := r.injectPodSpec(vectorSidecar, &wlCopy.Template.Spec, wl.isBatch())
+	if err != nil {
+		return err
 	}
 
-	// Handle init containers
-	if len(vectorSidecar.Spec.InitContainers) > 0 {
-		deploymentCopy.Spec.Template.Spec.InitContainers = append(
-			deploymentCopy.Spec.Template.Spec.InitContainers,
-			vectorSidecar.Spec.InitContainers...,
-		)
+	annotations := map[string]string{
+		AnnotationInjected:             "true",
+		AnnotationInjectedHash:         currentHash,
+		ownerAnnotation(vectorSidecar): vectorSidecar.Name,
 	}
 
-	// Update annotations
-	deploymentCopy.Annotations[AnnotationInjected] = "true"
-	deploymentCopy.Annotations[AnnotationInjectedHash] = currentHash
-	deploymentCopy.Annotations[AnnotationVectorSidecarName] = vectorSidecar.Name
-
 	// Store ConfigMap version if using ConfigMapRef
 	if vectorSidecar.Spec.Sidecar.Config.ConfigMapRef != nil {
 		cm := &corev1.ConfigMap{}
@@ -408,60 +770,122 @@ func (r *VectorSidecarReconciler) injectSidecar(ctx context.Context, vectorSidec
 			Namespace: vectorSidecar.Namespace,
 		}
 		if err := r.Get(ctx, cmName, cm); err == nil {
-			deploymentCopy.Annotations[AnnotationConfigMapVersion] = cm.ResourceVersion
+			annotations[AnnotationConfigMapVersion] = cm.ResourceVersion
 		}
 	}
 
-	deploymentCopy.Spec.Template.Annotations[AnnotationInjectedHash] = currentHash
+	templateAnnotations := map[string]string{AnnotationInjectedHash: currentHash}
+	if configHash != "" {
+		templateAnnotations[AnnotationConfigHash] = configHash
+	}
+
+	// Record what the injection adds so removal can restore the pod template exactly
+	manifest := newInjectionManifest(vectorSidecar, wl, &wlCopy.Template.Spec, templateAnnotations)
+	annotations[AnnotationInjectionManifest], err = manifest.encode()
+	if err != nil {
+		return err
+	}
 
-	// Update the deployment
-	if err := r.Update(ctx, deploymentCopy); err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+	// Apply the injected fields under the operator's field manager
+	intent, err := injectionIntent(wl, &wlCopy.Template.Spec, nativeSidecar, manifest, annotations, templateAnnotations)
+	if err != nil {
+		return err
 	}
+	if err := r.applyWorkload(ctx, wl, intent, func(applied *workload) {
+		tidyInjection(applied, nativeSidecar, manifest, previous, annotations, templateAnnotations)
+	}); err != nil {
+		return fmt.Errorf("failed to update %s: %w", wl.Kind, err)
+	}
+	injectionsTotal.WithLabelValues(injectionResultSuccess).Inc()
 
 	logger.Info("Successfully injected/updated sidecar - Kubernetes will perform rolling update",
-		"deployment", deployment.Name,
+		"workload", wl.String(),
 		"hash", currentHash,
 		"image", vectorSidecar.Spec.Sidecar.Image)
 	return nil
 }
 
-// removeSidecar removes the Vector sidecar from a deployment
-func (r *VectorSidecarReconciler) removeSidecar(ctx context.Context, deployment *appsv1.Deployment) error {
-	logger := log.FromContext(ctx)
-
-	deploymentCopy := deployment.DeepCopy()
-
-	// Remove Vector container
+// injectPodSpec renders the Vector container, its volumes and init containers into a pod spec.
+// It returns the name of the native sidecar init container, or an empty string in container mode.
+func (r *VectorSidecarReconciler) injectPodSpec(vectorSidecar *observabilityv1alpha1.VectorSidecar, podSpec *corev1.PodSpec, batch bool) (string, error) {
+	// Remove existing Vector container if present, from either placement, and the
+	// init containers the spec replaces by name
 	containers := []corev1.Container{}
-	for _, container := range deploymentCopy.Spec.Template.Spec.Containers {
-		if container.Name != "vector" {
+	initContainers := []corev1.Container{}
+	sidecarName := sidecarContainerName(vectorSidecar)
+	specInitContainers := map[string]bool{}
+	for _, container := range vectorSidecar.Spec.InitContainers {
+		specInitContainers[container.Name] = true
+	}
+
+	for _, container := range podSpec.Containers {
+		if container.Name != sidecarName {
 			containers = append(containers, container)
 		}
 	}
-	deploymentCopy.Spec.Template.Spec.Containers = containers
-
-	// Remove Vector config volume
-	volumes := []corev1.Volume{}
-	for _, volume := range deploymentCopy.Spec.Template.Spec.Volumes {
-		if volume.Name != VectorConfigVolumeName {
-			volumes = append(volumes, volume)
+	for _, container := range podSpec.InitContainers {
+		if container.Name != sidecarName && !specInitContainers[container.Name] {
+			initContainers = append(initContainers, container)
 		}
 	}
-	deploymentCopy.Spec.Template.Spec.Volumes = volumes
 
-	// Remove annotations
-	delete(deploymentCopy.Annotations, AnnotationInjected)
-	delete(deploymentCopy.Annotations, AnnotationInjectedHash)
-	delete(deploymentCopy.Annotations, AnnotationVectorSidecarName)
-	delete(deploymentCopy.Annotations, AnnotationConfigMapVersion)
-	delete(deploymentCopy.Spec.Template.Annotations, AnnotationInjectedHash)
+	// Build the Vector sidecar container
+	mode, err := r.podSidecarMode(vectorSidecar, batch)
+	if err != nil {
+		return "", err
+	}
+	vectorContainer := r.buildVectorContainer(vectorSidecar)
+	nativeSidecar := ""
+	if mode == observabilityv1alpha1.SidecarModeNative {
+		// Native sidecars start before the other init containers and stop after the app containers
+		initContainers = append([]corev1.Container{vectorContainer}, initContainers...)
+		nativeSidecar = sidecarName
+	} else {
+		containers = append(containers, vectorContainer)
+	}
+	podSpec.Containers = containers
+	podSpec.InitContainers = initContainers
+
+	// Handle volumes
+	if err := r.injectVolumes(vectorSidecar, podSpec); err != nil {
+		return "", fmt.Errorf("failed to inject volumes: %w", err)
+	}
+
+	// Handle init containers; they run after the workload's own, in spec order
+	if len(vectorSidecar.Spec.InitContainers) > 0 {
+		podSpec.InitContainers = append(
+			podSpec.InitContainers,
+			vectorSidecar.Spec.InitContainers...,
+		)
+	}
+
+	// Batch pods need Vector to exit once the application finishes; native sidecars
+	// are stopped by the kubelet so only clusters without them need the shutdown signal.
+	// Otherwise strip a signal left over from a previous container-mode injection.
+	if batch && mode != observabilityv1alpha1.SidecarModeNative {
+		injectShutdownSignal(podSpec, sidecarName)
+	} else {
+		removeShutdownSignal(podSpec, sidecarName)
+	}
 
-	if err := r.Update(ctx, deploymentCopy); err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+	return nativeSidecar, nil
+}
+
+// removeSidecar removes the Vector sidecar from a workload
+func (r *VectorSidecarReconciler) removeSidecar(ctx context.Context, vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload) error {
+	logger := log.FromContext(ctx)
+
+	// Release the fields the operator owns, then strip what the manifest recorded
+	// that an earlier update left under another field manager
+	manifest := recordedManifest(vectorSidecar, wl)
+	if err := r.applyWorkload(ctx, wl, emptyIntent(wl), func(applied *workload) {
+		tidyRemoval(applied, manifest)
+	}); err != nil {
+		return fmt.Errorf("failed to update %s: %w", wl.Kind, err)
 	}
+	removalsTotal.Inc()
 
-	logger.Info("Successfully removed sidecar", "deployment", deployment.Name)
+	logger.Info("Successfully removed sidecar", "workload", wl.String())
 	return nil
 }
 
@@ -469,13 +893,8 @@ func (r *VectorSidecarReconciler) removeSidecar(ctx context.Context, deployment
 func (r *VectorSidecarReconciler) buildVectorContainer(vectorSidecar *observabilityv1alpha1.VectorSidecar) corev1.Container {
 	sidecarSpec := vectorSidecar.Spec.Sidecar
 
-	containerName := sidecarSpec.Name
-	if containerName == "" {
-		containerName = "vector"
-	}
-
 	container := corev1.Container{
-		Name:            containerName,
+		Name:            sidecarContainerName(vectorSidecar),
 		Image:           sidecarSpec.Image,
 		ImagePullPolicy: sidecarSpec.ImagePullPolicy,
 		Resources:       sidecarSpec.Resources,
@@ -493,11 +912,14 @@ func (r *VectorSidecarReconciler) buildVectorContainer(vectorSidecar *observabil
 		args = append(args, sidecarSpec.Args...)
 	}
 
-	// Add config file argument
-	if sidecarSpec.Config.ConfigMapRef != nil {
-		args = append(args, "--config", "/etc/vector/vector.yaml")
-	} else if sidecarSpec.Config.Inline != "" {
-		args = append(args, "--config", "/etc/vector/vector.yaml")
+	// Add config file argument; the extension tells Vector which format to parse
+	if sidecarSpec.Config.ConfigMapRef != nil || sidecarSpec.Config.Inline != "" {
+		args = append(args, "--config", "/etc/vector/"+vectorConfigFileName(configSourceKey(vectorSidecar)))
+	}
+
+	// Let Vector reload the mounted file itself instead of restarting the pod
+	if configReloadPolicy(vectorSidecar) == observabilityv1alpha1.ConfigReloadPolicyHotReload {
+		args = append(args, "--watch-config")
 	}
 
 	container.Args = args
@@ -519,9 +941,28 @@ func (r *VectorSidecarReconciler) buildVectorContainer(vectorSidecar *observabil
 	return container
 }
 
-// injectVolumes adds necessary volumes to the deployment
-func (r *VectorSidecarReconciler) injectVolumes(vectorSidecar *observabilityv1alpha1.VectorSidecar, deployment *appsv1.Deployment) error {
-	volumes := deployment.Spec.Template.Spec.Volumes
+// defaultResyncInterval applies when the VectorSidecar leaves resyncInterval unset
+const defaultResyncInterval = 5 * time.Minute
+
+// resyncInterval returns how long a reconciled VectorSidecar waits before it is checked again
+func resyncInterval(vectorSidecar *observabilityv1alpha1.VectorSidecar) time.Duration {
+	if interval := vectorSidecar.Spec.ResyncInterval; interval != nil && interval.Duration > 0 {
+		return interval.Duration
+	}
+	return defaultResyncInterval
+}
+
+// sidecarContainerName returns the name of the Vector container, defaulting to DefaultSidecarName
+func sidecarContainerName(vectorSidecar *observabilityv1alpha1.VectorSidecar) string {
+	if vectorSidecar.Spec.Sidecar.Name == "" {
+		return observabilityv1alpha1.DefaultSidecarName
+	}
+	return vectorSidecar.Spec.Sidecar.Name
+}
+
+// injectVolumes adds necessary volumes to the pod spec
+func (r *VectorSidecarReconciler) injectVolumes(vectorSidecar *observabilityv1alpha1.VectorSidecar, podSpec *corev1.PodSpec) error {
+	volumes := podSpec.Volumes
 
 	// Remove existing vector-config volume if present
 	filteredVolumes := []corev1.Volume{}
@@ -538,10 +979,7 @@ func (r *VectorSidecarReconciler) injectVolumes(vectorSidecar *observabilityv1al
 	}
 
 	if vectorSidecar.Spec.Sidecar.Config.ConfigMapRef != nil {
-		key := vectorSidecar.Spec.Sidecar.Config.ConfigMapRef.Key
-		if key == "" {
-			key = "vector.yaml"
-		}
+		key := configSourceKey(vectorSidecar)
 		configVolume.VolumeSource = corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				LocalObjectReference: corev1.LocalObjectReference{
@@ -550,18 +988,23 @@ func (r *VectorSidecarReconciler) injectVolumes(vectorSidecar *observabilityv1al
 				Items: []corev1.KeyToPath{
 					{
 						Key:  key,
-						Path: "vector.yaml",
+						Path: vectorConfigFileName(key),
 					},
 				},
 			},
 		}
 	} else if vectorSidecar.Spec.Sidecar.Config.Inline != "" {
-		// For inline config, create a ConfigMap
-		cmName := fmt.Sprintf("%s-inline-config", vectorSidecar.Name)
+		// Inline config is materialized by reconcileInlineConfigMap
 		configVolume.VolumeSource = corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: cmName,
+					Name: inlineConfigMapName(vectorSidecar),
+				},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  InlineConfigKey,
+						Path: vectorConfigFileName(InlineConfigKey),
+					},
 				},
 			},
 		}
@@ -584,29 +1027,29 @@ func (r *VectorSidecarReconciler) injectVolumes(vectorSidecar *observabilityv1al
 		}
 	}
 
-	deployment.Spec.Template.Spec.Volumes = volumes
+	podSpec.Volumes = volumes
 	return nil
 }
 
-// calculateInjectionHash calculates a hash of the injection configuration
-func (r *VectorSidecarReconciler) calculateInjectionHash(vectorSidecar *observabilityv1alpha1.VectorSidecar) (string, error) {
-	// Create a struct containing all relevant fields for hashing
+// injectionHashVersion prefixes the injection hash. It only changes when the hash scheme itself
+// changes, so operator upgrades that render the same pod fragment keep every workload's hash.
+const injectionHashVersion = "v2"
+
+// calculateInjectionHash hashes the pod template fragment the VectorSidecar renders (the Vector
+// container in its placement, the volumes and the init containers) together with the hash of the
+// configuration content, so any change that reaches the pods rolls them and nothing else does
+func (r *VectorSidecarReconciler) calculateInjectionHash(vectorSidecar *observabilityv1alpha1.VectorSidecar, configHash string) (string, error) {
+	fragment := corev1.PodSpec{}
+	if _, err := r.injectPodSpec(vectorSidecar, &fragment, false); err != nil {
+		return "", err
+	}
+
 	hashData := struct {
-		Image        string
-		Config       observabilityv1alpha1.VectorConfig
-		VolumeMounts []corev1.VolumeMount
-		Resources    corev1.ResourceRequirements
-		Env          []corev1.EnvVar
-		Args         []string
-		Volumes      []corev1.Volume
+		PodSpec    corev1.PodSpec
+		ConfigHash string `json:",omitempty"`
 	}{
-		Image:        vectorSidecar.Spec.Sidecar.Image,
-		Config:       vectorSidecar.Spec.Sidecar.Config,
-		VolumeMounts: vectorSidecar.Spec.Sidecar.VolumeMounts,
-		Resources:    vectorSidecar.Spec.Sidecar.Resources,
-		Env:          vectorSidecar.Spec.Sidecar.Env,
-		Args:         vectorSidecar.Spec.Sidecar.Args,
-		Volumes:      vectorSidecar.Spec.Volumes,
+		PodSpec:    fragment,
+		ConfigHash: configHash,
 	}
 
 	// Marshal to JSON for consistent hashing
@@ -617,7 +1060,7 @@ func (r *VectorSidecarReconciler) calculateInjectionHash(vectorSidecar *observab
 
 	// Calculate SHA256 hash
 	hash := sha256.Sum256(jsonData)
-	return fmt.Sprintf("%x", hash[:8]), nil
+	return fmt.Sprintf("%s-%x", injectionHashVersion, hash[:8]), nil
 }
 
 // updateStatusCondition updates or adds a condition to the status
@@ -638,8 +1081,60 @@ func (r *VectorSidecarReconciler) updateStatusCondition(ctx context.Context, vec
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VectorSidecarReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&observabilityv1alpha1.VectorSidecar{}).
-		Owns(&appsv1.Deployment{}).
-		Complete(r)
+	// Index VectorSidecars by referenced ConfigMap so ConfigMap events map back to them
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &observabilityv1alpha1.VectorSidecar{},
+		configMapRefIndexField, indexConfigMapRef); err != nil {
+		return err
+	}
+
+	// Index workloads by the resource that injected them
+	for _, kind := range supportedWorkloadKinds {
+		obj, err := newWorkloadObject(kind)
+		if err != nil {
+			return err
+		}
+		for field, annotation := range ownerIndexFields {
+			if err := mgr.GetFieldIndexer().IndexField(context.Background(), obj, field, indexAnnotation(annotation)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The orphan collector hands workloads to their heirs through this channel
+	r.adoptions = make(chan event.GenericEvent, adoptionQueueSize)
+
+	// Status writes do not requeue the VectorSidecar; the reconcile that wrote them already
+	// scheduled the next pass through RequeueAfter
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.Options.controllerOptions()).
+		For(&observabilityv1alpha1.VectorSidecar{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		))).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.vectorSidecarsForConfigMap)).
+		Watches(&source.Kind{Type: &observabilityv1alpha1.VectorSidecar{}},
+			handler.EnqueueRequestsFromMapFunc(r.vectorSidecarsInConflict),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Channel{Source: r.adoptions}, &handler.EnqueueRequestForObject{})
+
+	// Target workloads carry no owner reference, so map their events to VectorSidecars by
+	// selector. Status-only updates are filtered out; they never change what is injected.
+	for _, kind := range supportedWorkloadKinds {
+		obj, err := newWorkloadObject(kind)
+		if err != nil {
+			return err
+		}
+		bldr = bldr.Watches(&source.Kind{Type: obj},
+			handler.EnqueueRequestsFromMapFunc(r.vectorSidecarsForWorkload),
+			builder.WithPredicates(predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			)))
+	}
+
+	return bldr.Complete(r)
 }
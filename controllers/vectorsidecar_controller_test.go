@@ -18,19 +18,37 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/version"
+	discoveryfake "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
 )
@@ -52,7 +70,7 @@ var _ = Describe("VectorSidecar Controller", func() {
 					Namespace: "default",
 				},
 				Data: map[string]string{
-					"vector.yaml": "# Vector configuration\nsources: {}\nsinks: {}",
+					"vector.yaml": validVectorConfig,
 				},
 			}
 
@@ -147,8 +165,7 @@ var _ = Describe("VectorSidecar Controller", func() {
 			s := scheme.Scheme
 			_ = observabilityv1alpha1.AddToScheme(s)
 
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(s).
+			fakeClient := newFakeClientBuilder(s).
 				WithObjects(configMap, deployment, vectorSidecar).
 				Build()
 
@@ -238,8 +255,7 @@ var _ = Describe("VectorSidecar Controller", func() {
 			s := scheme.Scheme
 			_ = observabilityv1alpha1.AddToScheme(s)
 
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(s).
+			fakeClient := newFakeClientBuilder(s).
 				WithObjects(vectorSidecar).
 				Build()
 
@@ -288,7 +304,7 @@ var _ = Describe("VectorSidecar Controller", func() {
 					Namespace: "default",
 				},
 				Data: map[string]string{
-					"vector.yaml": "sources: {}\nsinks: {}",
+					"vector.yaml": validVectorConfig,
 				},
 			}
 
@@ -360,8 +376,7 @@ var _ = Describe("VectorSidecar Controller", func() {
 			s := scheme.Scheme
 			_ = observabilityv1alpha1.AddToScheme(s)
 
-			fakeClient := fake.NewClientBuilder().
-				WithScheme(s).
+			fakeClient := newFakeClientBuilder(s).
 				WithObjects(configMap, deployment, vectorSidecar).
 				Build()
 
@@ -402,6 +417,3228 @@ var _ = Describe("VectorSidecar Controller", func() {
 			Expect(containers[0].Name).To(Equal("app"))
 		})
 
+		It("Should materialize inline configuration as an owned ConfigMap", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-inline",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"observability": "vector-inline",
+						},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							Inline: validVectorConfig,
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(vectorSidecar).
+				Build()
+
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-vectorsidecar-inline",
+					Namespace: "default",
+				},
+			}
+
+			// First reconcile to add finalizer
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Second reconcile should create the ConfigMap
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := &corev1.ConfigMap{}
+			cmKey := types.NamespacedName{Name: "test-vectorsidecar-inline-inline-config", Namespace: "default"}
+			Expect(fakeClient.Get(ctx, cmKey, cm)).To(Succeed())
+			Expect(cm.Data[InlineConfigKey]).To(Equal(validVectorConfig))
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+			Expect(cm.OwnerReferences[0].Name).To(Equal("test-vectorsidecar-inline"))
+			Expect(cm.Annotations[AnnotationVectorSidecarName]).To(Equal("test-vectorsidecar-inline"))
+
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			inlineCondition := findCondition(updatedVS.Status.Conditions, observabilityv1alpha1.ConditionTypeInlineConfigReady)
+			Expect(inlineCondition).NotTo(BeNil())
+			Expect(inlineCondition.Status).To(Equal(metav1.ConditionTrue))
+
+			// Update inline content and verify the ConfigMap follows
+			updatedConfig := strings.Replace(validVectorConfig, "codec: json", "codec: text", 1)
+			updatedVS.Spec.Sidecar.Config.Inline = updatedConfig
+			Expect(fakeClient.Update(ctx, updatedVS)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, cmKey, cm)).To(Succeed())
+			Expect(cm.Data[InlineConfigKey]).To(Equal(updatedConfig))
+
+			// Adding a ConfigMapRef should garbage-collect the generated ConfigMap, as the
+			// reference takes precedence over the inline content
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-inline-ref", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			})).To(Succeed())
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			updatedVS.Spec.Sidecar.Config.ConfigMapRef = &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-inline-ref"}
+			Expect(fakeClient.Update(ctx, updatedVS)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			err = fakeClient.Get(ctx, cmKey, cm)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should inject sidecar into the configured target kinds", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vector-config-kinds",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"vector.yaml": validVectorConfig,
+				},
+			}
+
+			podTemplate := corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "kafka"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "kafka:latest"}},
+				},
+			}
+
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-statefulset",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-kinds"},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+					Template: podTemplate,
+				},
+			}
+
+			// Deployment-managed ReplicaSets must be left to their Deployment
+			ownedReplicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-owned-replicaset",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-kinds"},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "owner",
+						UID:        "owner-uid",
+						Controller: boolPtr(true),
+					}},
+				},
+				Spec: appsv1.ReplicaSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+					Template: podTemplate,
+				},
+			}
+
+			// Deployments are not targeted unless listed in targetKinds
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-kinds",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-kinds"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kafka"}},
+					Template: podTemplate,
+				},
+			}
+
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-kinds",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-kinds"},
+					},
+					TargetKinds: []observabilityv1alpha1.WorkloadKind{
+						observabilityv1alpha1.WorkloadKindStatefulSet,
+						observabilityv1alpha1.WorkloadKindReplicaSet,
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-kinds"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, statefulSet, ownedReplicaSet, deployment, vectorSidecar).
+				Build()
+
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-vectorsidecar-kinds",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedStatefulSet := &appsv1.StatefulSet{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(statefulSet), updatedStatefulSet)).To(Succeed())
+			Expect(updatedStatefulSet.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(updatedStatefulSet.Annotations[AnnotationVectorSidecarName]).To(Equal("test-vectorsidecar-kinds"))
+
+			updatedReplicaSet := &appsv1.ReplicaSet{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ownedReplicaSet), updatedReplicaSet)).To(Succeed())
+			Expect(updatedReplicaSet.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+			updatedDeployment := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), updatedDeployment)).To(Succeed())
+			Expect(updatedDeployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			Expect(updatedVS.Status.MatchedDeployments).To(Equal(int32(1)))
+		})
+
+		It("Should inject a shutdown-aware sidecar into CronJobs", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vector-config-batch",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"vector.yaml": validVectorConfig,
+				},
+			}
+
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cronjob",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-batch"},
+				},
+				Spec: batchv1.CronJobSpec{
+					Schedule: "*/5 * * * *",
+					JobTemplate: batchv1.JobTemplateSpec{
+						Spec: batchv1.JobSpec{
+							Template: corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									RestartPolicy: corev1.RestartPolicyNever,
+									Containers:    []corev1.Container{{Name: "app", Image: "busybox:latest"}},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-batch",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-batch"},
+					},
+					TargetKinds: []observabilityv1alpha1.WorkloadKind{observabilityv1alpha1.WorkloadKindCronJob},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-batch"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, cronJob, vectorSidecar).
+				Build()
+
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-vectorsidecar-batch",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedCronJob := &batchv1.CronJob{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cronJob), updatedCronJob)).To(Succeed())
+			podSpec := updatedCronJob.Spec.JobTemplate.Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(2))
+
+			app, vector := podSpec.Containers[0], podSpec.Containers[1]
+			Expect(vector.Name).To(Equal("vector"))
+			Expect(vector.Command).To(ContainElement(shutdownWrapperScript))
+			Expect(vector.Args).To(ContainElement("--config"))
+			Expect(app.Env).To(ContainElement(corev1.EnvVar{Name: ShutdownFileEnvVar, Value: shutdownFilePath}))
+			Expect(app.VolumeMounts).To(ContainElement(HaveField("Name", LifecycleVolumeName)))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", LifecycleVolumeName)))
+			Expect(podSpec.ShareProcessNamespace).To(HaveValue(BeTrue()))
+
+			// Disabling must strip the shutdown signal from the application container
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			updatedVS.Spec.Enabled = false
+			Expect(fakeClient.Update(ctx, updatedVS)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cronJob), updatedCronJob)).To(Succeed())
+			podSpec = updatedCronJob.Spec.JobTemplate.Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.Containers[0].Env).To(BeEmpty())
+			Expect(podSpec.Containers[0].VolumeMounts).To(BeEmpty())
+			Expect(podSpec.Volumes).NotTo(ContainElement(HaveField("Name", LifecycleVolumeName)))
+			Expect(podSpec.ShareProcessNamespace).To(BeNil())
+		})
+
+		It("Should use a native sidecar for batch pods on clusters that support it", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Name:  "log-shipper",
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config"},
+						},
+					},
+				},
+			}
+			podSpec := &corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "busybox:latest"}},
+			}
+
+			// Without native sidecars the wrapper stops Vector
+			older := &VectorSidecarReconciler{Discovery: fakeDiscovery("v1.28.4")}
+			_, err := older.injectPodSpec(vectorSidecar, podSpec, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", LifecycleVolumeName)))
+			Expect(podSpec.Containers[1].Command).To(Equal(shutdownWrapperCommand))
+			Expect(shutdownHandlingOutdated(podSpec, "log-shipper", false)).To(BeFalse())
+
+			// Once the cluster supports them, batch pods move to a native sidecar even in
+			// container mode, and the shutdown signal is stripped
+			reconciler := &VectorSidecarReconciler{Discovery: fakeDiscovery("v1.29.2")}
+			Expect(shutdownHandlingOutdated(podSpec, "log-shipper", true)).To(BeTrue())
+			nativeSidecar, err := reconciler.injectPodSpec(vectorSidecar, podSpec, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nativeSidecar).To(Equal("log-shipper"))
+			Expect(shutdownHandlingOutdated(podSpec, "log-shipper", true)).To(BeFalse())
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.Containers[0].Env).To(BeEmpty())
+			Expect(podSpec.Containers[0].VolumeMounts).To(BeEmpty())
+			Expect(podSpec.Volumes).NotTo(ContainElement(HaveField("Name", LifecycleVolumeName)))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("log-shipper"))
+		})
+
+		It("Should render init containers by name in a stable order", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Mode:  observabilityv1alpha1.SidecarModeNative,
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config"},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{Name: "setup", Image: "busybox:1.36"},
+						{Name: "migrate", Image: "busybox:1.36"},
+					},
+				},
+			}
+			reconciler := &VectorSidecarReconciler{Discovery: fakeDiscovery("v1.29.2")}
+
+			podSpec := &corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "wait-db", Image: "busybox:latest"}},
+				Containers:     []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+			}
+			names := func() []string {
+				var names []string
+				for _, container := range podSpec.InitContainers {
+					names = append(names, container.Name)
+				}
+				return names
+			}
+
+			for _, image := range []string{"busybox:1.36", "busybox:1.37", "busybox:1.38"} {
+				vectorSidecar.Spec.InitContainers[0].Image = image
+				_, err := reconciler.injectPodSpec(vectorSidecar, podSpec, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(names()).To(Equal([]string{"vector", "wait-db", "setup", "migrate"}))
+				Expect(podSpec.InitContainers[2].Image).To(Equal(image))
+			}
+
+			// Init containers are part of the hash, so editing them rolls the workload
+			before, err := reconciler.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).NotTo(HaveOccurred())
+			vectorSidecar.Spec.InitContainers[1].Args = []string{"--dry-run"}
+			after, err := reconciler.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after).NotTo(Equal(before))
+		})
+
+		It("Should replace, add and remove injected init containers on workloads", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-init", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-init",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-init"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "init"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "init"}},
+						Spec: corev1.PodSpec{
+							InitContainers: []corev1.Container{{Name: "wait-db", Image: "busybox:latest"}},
+							Containers:     []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-init",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-init"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-init"},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{Name: "setup", Image: "busybox:1.36"},
+						{Name: "migrate", Image: "busybox:1.36"},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-init", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-init", Namespace: "default"}
+			initContainers := func() []string {
+				updated := &appsv1.Deployment{}
+				Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+				var names []string
+				for _, container := range updated.Spec.Template.Spec.InitContainers {
+					names = append(names, container.Name+"="+container.Image)
+				}
+				return names
+			}
+			update := func(mutate func(*observabilityv1alpha1.VectorSidecar)) {
+				current := &observabilityv1alpha1.VectorSidecar{}
+				Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+				mutate(current)
+				Expect(fakeClient.Update(ctx, current)).To(Succeed())
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(initContainers()).To(Equal([]string{"wait-db=busybox:latest", "setup=busybox:1.36", "migrate=busybox:1.36"}))
+
+			// Editing an init container rolls it out in place instead of adding a copy
+			update(func(vs *observabilityv1alpha1.VectorSidecar) { vs.Spec.InitContainers[0].Image = "busybox:1.37" })
+			update(func(vs *observabilityv1alpha1.VectorSidecar) { vs.Spec.InitContainers[0].Image = "busybox:1.38" })
+			Expect(initContainers()).To(Equal([]string{"wait-db=busybox:latest", "setup=busybox:1.38", "migrate=busybox:1.36"}))
+
+			// Dropping an init container from the spec removes it from the workload
+			update(func(vs *observabilityv1alpha1.VectorSidecar) { vs.Spec.InitContainers = vs.Spec.InitContainers[:1] })
+			Expect(initContainers()).To(Equal([]string{"wait-db=busybox:latest", "setup=busybox:1.38"}))
+
+			update(func(vs *observabilityv1alpha1.VectorSidecar) { vs.Spec.Enabled = false })
+			Expect(initContainers()).To(Equal([]string{"wait-db=busybox:latest"}))
+		})
+
+		It("Should inject Vector as a native sidecar on supported clusters", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vector-config-native",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"vector.yaml": validVectorConfig,
+				},
+			}
+
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-native",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-native"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "native"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "native"}},
+						Spec: corev1.PodSpec{
+							InitContainers: []corev1.Container{{Name: "migrate", Image: "busybox:latest"}},
+							Containers:     []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-native",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-native"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Mode:  observabilityv1alpha1.SidecarModeNative,
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-native"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+
+			reconciler := &VectorSidecarReconciler{
+				Client:    fakeClient,
+				Scheme:    s,
+				Recorder:  record.NewFakeRecorder(10),
+				Discovery: fakeDiscovery("v1.29.2"),
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-vectorsidecar-native",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedDeployment := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), updatedDeployment)).To(Succeed())
+			podSpec := updatedDeployment.Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.InitContainers).To(HaveLen(2))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("vector"))
+			Expect(podSpec.InitContainers[1].Name).To(Equal("migrate"))
+
+			// Switching back to container mode moves Vector out of the init containers
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			updatedVS.Spec.Sidecar.Mode = observabilityv1alpha1.SidecarModeContainer
+			Expect(fakeClient.Update(ctx, updatedVS)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), updatedDeployment)).To(Succeed())
+			podSpec = updatedDeployment.Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(2))
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("migrate"))
+		})
+
+		It("Should fall back to container mode on clusters without native sidecars", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Mode:  observabilityv1alpha1.SidecarModeNative,
+						Image: "timberio/vector:0.35.0",
+					},
+				},
+			}
+
+			oldCluster := &VectorSidecarReconciler{Discovery: fakeDiscovery("v1.28.5")}
+			Expect(oldCluster.sidecarMode(vectorSidecar)).To(Equal(observabilityv1alpha1.SidecarModeContainer))
+
+			newCluster := &VectorSidecarReconciler{Discovery: fakeDiscovery("v1.30.1-gke.1000")}
+			Expect(newCluster.sidecarMode(vectorSidecar)).To(Equal(observabilityv1alpha1.SidecarModeNative))
+
+			// The effective placement is part of the injection hash
+			oldHash, err := oldCluster.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).NotTo(HaveOccurred())
+			newHash, err := newCluster.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newHash).NotTo(Equal(oldHash))
+		})
+
+		It("Should not downgrade native mode when server version detection fails", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Mode:  observabilityv1alpha1.SidecarModeNative,
+						Image: "timberio/vector:0.35.0",
+					},
+				},
+			}
+
+			discovery := &flakyDiscovery{
+				info: &version.Info{GitVersion: "v1.29.2"},
+				err:  errors.New("connection refused"),
+			}
+			reconciler := &VectorSidecarReconciler{Discovery: discovery}
+
+			_, err := reconciler.sidecarMode(vectorSidecar)
+			Expect(err).To(HaveOccurred())
+			_, err = reconciler.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).To(HaveOccurred())
+
+			// The next successful detection is used and cached
+			discovery.err = nil
+			Expect(reconciler.sidecarMode(vectorSidecar)).To(Equal(observabilityv1alpha1.SidecarModeNative))
+		})
+
+		It("Should report the native sidecar fallback once", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-fallback", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-fallback",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-fallback"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Mode:  observabilityv1alpha1.SidecarModeNative,
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-fallback"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, vectorSidecar).
+				Build()
+
+			recorder := record.NewFakeRecorder(20)
+			reconciler := &VectorSidecarReconciler{
+				Client:    fakeClient,
+				Scheme:    s,
+				Recorder:  recorder,
+				Discovery: fakeDiscovery("v1.28.5"),
+			}
+
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vectorSidecar)}
+			for i := 0; i < 3; i++ {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			warnings := 0
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, "NativeSidecarUnsupported") {
+					warnings++
+				}
+			}
+			Expect(warnings).To(Equal(1))
+
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			condition := findCondition(updatedVS.Status.Conditions, observabilityv1alpha1.ConditionTypeNativeSidecarSupported)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("Should set restartPolicy Always on the native sidecar in the patch", func() {
+			patch := []byte(`{"spec":{"template":{"spec":{"initContainers":[{"name":"vector","image":"timberio/vector:0.35.0"},{"$patch":"delete","name":"old"}]}}}}`)
+
+			updated, err := setNativeSidecarRestartPolicy(patch, []string{"spec", "template", "spec"}, "vector")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(updated)).To(ContainSubstring(`"name":"vector","restartPolicy":"Always"`))
+			Expect(string(updated)).To(ContainSubstring(`{"$patch":"delete","name":"old"}`))
+
+			// Patches that do not touch the pod spec are left unchanged
+			unchanged := []byte(`{"metadata":{"annotations":{"a":"b"}}}`)
+			updated, err = setNativeSidecarRestartPolicy(unchanged, []string{"spec", "template", "spec"}, "vector")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(Equal(unchanged))
+		})
+
+		It("Should inject into pods through the admission webhook", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vector-config-webhook",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"vector.yaml": validVectorConfig,
+				},
+			}
+
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vectorsidecar-webhook",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled:           true,
+					InjectionStrategy: observabilityv1alpha1.InjectionStrategyPod,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-webhook"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-webhook"},
+						},
+					},
+					InitContainers: []corev1.Container{{Name: "setup", Image: "busybox:latest"}},
+				},
+			}
+
+			// Workloads are not touched when pods are injected by the webhook
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-webhook",
+					Namespace: "default",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"observability": "vector-webhook"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"observability": "vector-webhook"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, vectorSidecar, deployment).
+				Build()
+
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test-vectorsidecar-webhook",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedDeployment := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), updatedDeployment)).To(Succeed())
+			Expect(updatedDeployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(updatedDeployment.Annotations).NotTo(HaveKey(AnnotationInjected))
+
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			Expect(updatedVS.Status.MatchedDeployments).To(Equal(int32(1)))
+			readyCondition := findCondition(updatedVS.Status.Conditions, observabilityv1alpha1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal("PodInjectionActive"))
+
+			decoder, err := admission.NewDecoder(s)
+			Expect(err).NotTo(HaveOccurred())
+			injector := &podInjector{reconciler: reconciler, decoder: decoder}
+
+			// The existing native sidecar carries a field the typed client does not know about
+			rawPod := []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"generateName":"web-","labels":{"observability":"vector-webhook"}},` +
+				`"spec":{"initContainers":[{"name":"proxy","image":"envoy:latest","restartPolicy":"Always"}],"containers":[{"name":"app","image":"nginx:latest"}]}}`)
+			resp := injector.Handle(ctx, admissionRequest("default", rawPod))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(ContainElement(And(
+				HaveField("Operation", "add"),
+				HaveField("Path", "/spec/containers/1"),
+				HaveField("Value", HaveKeyWithValue("name", "vector")),
+			)))
+			Expect(resp.Patches).To(ContainElement(HaveField("Value", HaveKeyWithValue("name", "setup"))))
+			Expect(resp.Patches).To(ContainElement(HaveField("Path", "/metadata/annotations")))
+			Expect(resp.Patches).NotTo(ContainElement(HaveField("Path", HaveSuffix("restartPolicy"))))
+
+			// Pods outside the selector are admitted unchanged
+			rawPod = []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"other","labels":{"app":"other"}},` +
+				`"spec":{"containers":[{"name":"app","image":"nginx:latest"}]}}`)
+			resp = injector.Handle(ctx, admissionRequest("default", rawPod))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(BeEmpty())
+		})
+
+		It("Should roll pods when referenced ConfigMap content changes", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-reload", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-reload",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-reload"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "reload"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "reload"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-reload",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-reload"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-reload"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				WithIndex(&observabilityv1alpha1.VectorSidecar{}, configMapRefIndexField, indexConfigMapRef).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			// ConfigMap events map back to the VectorSidecars that reference it
+			Expect(reconciler.vectorSidecarsForConfigMap(configMap)).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-reload", Namespace: "default"},
+			}))
+			Expect(reconciler.vectorSidecarsForConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"},
+			})).To(BeEmpty())
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-reload", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-reload", Namespace: "default"}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			firstConfigHash := updated.Spec.Template.Annotations[AnnotationConfigHash]
+			Expect(firstConfigHash).NotTo(BeEmpty())
+
+			// A content change stamps a new hash on the pod template under RestartPods
+			configMap.Data["vector.yaml"] = strings.Replace(validVectorConfig, "codec: json", "codec: text", 1)
+			Expect(fakeClient.Update(ctx, configMap)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Annotations[AnnotationConfigHash]).NotTo(Equal(firstConfigHash))
+
+			// HotReload hands content changes to Vector and stops stamping the content hash
+			Expect(fakeClient.Get(ctx, req.NamespacedName, vectorSidecar)).To(Succeed())
+			vectorSidecar.Spec.ConfigReloadPolicy = observabilityv1alpha1.ConfigReloadPolicyHotReload
+			Expect(fakeClient.Update(ctx, vectorSidecar)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Annotations).NotTo(HaveKey(AnnotationConfigHash))
+			Expect(updated.Spec.Template.Spec.Containers[1].Args).To(ContainElement("--watch-config"))
+			hotReloadHash := updated.Spec.Template.Annotations[AnnotationInjectedHash]
+
+			configMap.Data["vector.yaml"] = validVectorConfig
+			Expect(fakeClient.Update(ctx, configMap)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Annotations[AnnotationInjectedHash]).To(Equal(hotReloadHash))
+		})
+
+		It("Should map workload events to the VectorSidecars selecting them", func() {
+			newVectorSidecar := func(name string, selector map[string]string, mutate func(*observabilityv1alpha1.VectorSidecarSpec)) *observabilityv1alpha1.VectorSidecar {
+				vs := &observabilityv1alpha1.VectorSidecar{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: observabilityv1alpha1.VectorSidecarSpec{
+						Enabled:  true,
+						Selector: metav1.LabelSelector{MatchLabels: selector},
+					},
+				}
+				if mutate != nil {
+					mutate(&vs.Spec)
+				}
+				return vs
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(
+					newVectorSidecar("by-labels", map[string]string{"observability": "vector-watch"}, nil),
+					newVectorSidecar("by-pod-labels", map[string]string{"app": "watch"}, func(spec *observabilityv1alpha1.VectorSidecarSpec) {
+						spec.InjectionStrategy = observabilityv1alpha1.InjectionStrategyPod
+					}),
+					newVectorSidecar("statefulsets-only", map[string]string{"observability": "vector-watch"}, func(spec *observabilityv1alpha1.VectorSidecarSpec) {
+						spec.TargetKinds = []observabilityv1alpha1.WorkloadKind{observabilityv1alpha1.WorkloadKindStatefulSet}
+					}),
+					newVectorSidecar("previous-owner", map[string]string{"observability": "other"}, nil),
+					newVectorSidecar("unrelated", map[string]string{"observability": "other"}, nil),
+				).
+				Build()
+			reconciler := &VectorSidecarReconciler{Client: fakeClient, Scheme: s}
+
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-deployment-watch",
+					Namespace:   "default",
+					Labels:      map[string]string{"observability": "vector-watch"},
+					Annotations: map[string]string{AnnotationVectorSidecarName: "previous-owner"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "watch"}},
+					},
+				},
+			}
+
+			var names []string
+			for _, req := range reconciler.vectorSidecarsForWorkload(deployment) {
+				names = append(names, req.Name)
+			}
+			Expect(names).To(ConsistOf("by-labels", "by-pod-labels", "previous-owner"))
+
+			// ReplicaSets managed by a Deployment are reconciled through their owner
+			replicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "test-deployment-watch-abc",
+					Namespace:       "default",
+					Labels:          map[string]string{"observability": "vector-watch"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "test-deployment-watch", Controller: boolPtr(true)}},
+				},
+			}
+			Expect(reconciler.vectorSidecarsForWorkload(replicaSet)).To(BeEmpty())
+		})
+
+		It("Should restore a sidecar stripped from a workload by hand", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-drift", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "drift-app",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-drift"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "drift-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "drift-app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-drift",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-drift"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-drift"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).WithObjects(configMap, deployment, vectorSidecar).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: recorder,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-drift", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			// Someone strips the Vector container and its config volume but keeps the annotations
+			stripped := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), stripped)).To(Succeed())
+			Expect(stripped.Spec.Template.Spec.Containers).To(HaveLen(2))
+			stripped.Spec.Template.Spec.Containers = stripped.Spec.Template.Spec.Containers[:1]
+			stripped.Spec.Template.Spec.Volumes = nil
+			Expect(fakeClient.Update(ctx, stripped)).To(Succeed())
+
+			// The workload event maps back to the VectorSidecar, which re-applies the injection
+			Expect(reconciler.vectorSidecarsForWorkload(stripped)).To(ConsistOf(req))
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("DriftDetected")))
+
+			restored := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), restored)).To(Succeed())
+			Expect(restored.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(restored.Spec.Template.Spec.Containers[1].Name).To(Equal("vector"))
+			Expect(restored.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", VectorConfigVolumeName)))
+		})
+
+		It("Should push selectors and owner lookups down to the cache", func() {
+			deployment := func(name string, labels, annotations map[string]string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, Annotations: annotations},
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}}},
+						},
+					},
+				}
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-vectorsidecar-pushdown", Namespace: "default"},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled:  true,
+					Selector: metav1.LabelSelector{MatchLabels: map[string]string{"observability": "vector-pushdown"}},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			lister := &listRecordingClient{Client: newFakeClientBuilder(s).
+				WithObjects(
+					deployment("pushdown-matched", map[string]string{"observability": "vector-pushdown"}, nil),
+					deployment("pushdown-injected", nil, map[string]string{AnnotationVectorSidecarName: "test-vectorsidecar-pushdown"}),
+					deployment("pushdown-other", map[string]string{"observability": "other"},
+						map[string]string{AnnotationVectorSidecarName: "another-vectorsidecar"}),
+				).
+				Build()}
+			reconciler := &VectorSidecarReconciler{Client: lister, Scheme: s}
+
+			// Matching lists typed workloads filtered by the selector
+			matched, err := reconciler.getMatchingWorkloads(ctx, vectorSidecar)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(HaveLen(1))
+			Expect(matched[0].GetName()).To(Equal("pushdown-matched"))
+			Expect(matched[0].Template.Spec.Containers).To(HaveLen(1))
+			Expect(lister.lists).To(HaveLen(1))
+			Expect(lister.lists[0]).To(BeAssignableToTypeOf(&appsv1.DeploymentList{}))
+			Expect(lister.options[0].LabelSelector.String()).To(Equal("observability=vector-pushdown"))
+
+			// Injected workloads are looked up through the owner annotation index
+			lister.lists, lister.options = nil, nil
+			injected, err := reconciler.getInjectedWorkloads(ctx, vectorSidecar)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(injected).To(HaveLen(1))
+			Expect(injected[0].GetName()).To(Equal("pushdown-injected"))
+			Expect(lister.options).To(HaveLen(len(supportedWorkloadKinds)))
+			for _, options := range lister.options {
+				Expect(options.FieldSelector.String()).To(Equal(sidecarNameIndexField + "=test-vectorsidecar-pushdown"))
+			}
+		})
+
+		It("Should let only the VectorSidecar with the highest precedence inject a workload", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-conflict", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-conflict",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-conflict"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "conflict"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "conflict"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			newVectorSidecar := func(name string, priority int32, image string) *observabilityv1alpha1.VectorSidecar {
+				return &observabilityv1alpha1.VectorSidecar{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       name,
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
+					},
+					Spec: observabilityv1alpha1.VectorSidecarSpec{
+						Enabled:  true,
+						Priority: priority,
+						Selector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"observability": "vector-conflict"},
+						},
+						Sidecar: observabilityv1alpha1.SidecarConfig{
+							Image: image,
+							Config: observabilityv1alpha1.VectorConfig{
+								ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-conflict"},
+							},
+						},
+					},
+				}
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment,
+					newVectorSidecar("a-low", 0, "timberio/vector:0.34.0"),
+					newVectorSidecar("b-high", 10, "timberio/vector:0.35.0"),
+				).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			lowReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: "a-low", Namespace: "default"}}
+			highReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: "b-high", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-conflict", Namespace: "default"}
+
+			// Reconciling both in either order converges on the higher priority without flip-flopping
+			for _, req := range []reconcile.Request{lowReq, highReq, lowReq} {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			updated := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Annotations[AnnotationVectorSidecarName]).To(Equal("b-high"))
+			Expect(updated.Spec.Template.Spec.Containers[1].Image).To(Equal("timberio/vector:0.35.0"))
+
+			loser := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, lowReq.NamespacedName, loser)).To(Succeed())
+			conflict := findCondition(loser.Status.Conditions, observabilityv1alpha1.ConditionTypeConflict)
+			Expect(conflict).NotTo(BeNil())
+			Expect(conflict.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflict.Message).To(ContainSubstring("Deployment/test-deployment-conflict"))
+			Expect(conflict.Message).To(ContainSubstring("VectorSidecar b-high"))
+			Expect(loser.Status.InjectedDeployments).To(BeZero())
+
+			winner := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, highReq.NamespacedName, winner)).To(Succeed())
+			Expect(findCondition(winner.Status.Conditions, observabilityv1alpha1.ConditionTypeConflict).Status).To(Equal(metav1.ConditionFalse))
+
+			// The loser is requeued when the winner changes, and on a tie the name sorts first
+			Expect(reconciler.vectorSidecarsInConflict(winner)).To(ConsistOf(lowReq))
+			winner.Spec.Priority = 0
+			Expect(fakeClient.Update(ctx, winner)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, lowReq)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Annotations[AnnotationVectorSidecarName]).To(Equal("a-low"))
+			Expect(updated.Spec.Template.Spec.Containers[1].Image).To(Equal("timberio/vector:0.34.0"))
+		})
+
+		It("Should keep the workloads of a winner that switches to dry run", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-dryrun-claim", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-dryrun-claim",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-dryrun-claim"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "dryrun-claim"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "dryrun-claim"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			newVectorSidecar := func(name string, priority int32, image string) *observabilityv1alpha1.VectorSidecar {
+				return &observabilityv1alpha1.VectorSidecar{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       name,
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
+					},
+					Spec: observabilityv1alpha1.VectorSidecarSpec{
+						Enabled:  true,
+						Priority: priority,
+						Selector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"observability": "vector-dryrun-claim"},
+						},
+						Sidecar: observabilityv1alpha1.SidecarConfig{
+							Image: image,
+							Config: observabilityv1alpha1.VectorConfig{
+								ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-dryrun-claim"},
+							},
+						},
+					},
+				}
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment,
+					newVectorSidecar("claim-low", 0, "timberio/vector:0.34.0"),
+					newVectorSidecar("claim-high", 10, "timberio/vector:0.35.0"),
+				).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(20),
+			}
+
+			lowReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: "claim-low", Namespace: "default"}}
+			highReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: "claim-high", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-dryrun-claim", Namespace: "default"}
+			for _, req := range []reconcile.Request{highReq, lowReq} {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			injected := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, injected)).To(Succeed())
+			Expect(injected.Annotations[AnnotationVectorSidecarName]).To(Equal("claim-high"))
+
+			// The winner switches to dry run with a new image; neither resource writes the workload
+			winner := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, highReq.NamespacedName, winner)).To(Succeed())
+			winner.Spec.Mode = observabilityv1alpha1.ReconcileModeDryRun
+			winner.Spec.Sidecar.Image = "timberio/vector:0.36.0"
+			Expect(fakeClient.Update(ctx, winner)).To(Succeed())
+			for _, req := range []reconcile.Request{highReq, lowReq, highReq} {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			unchanged := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, unchanged)).To(Succeed())
+			Expect(unchanged.Annotations[AnnotationVectorSidecarName]).To(Equal("claim-high"))
+			Expect(unchanged.Spec.Template).To(Equal(injected.Spec.Template))
+
+			loser := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, lowReq.NamespacedName, loser)).To(Succeed())
+			conflict := findCondition(loser.Status.Conditions, observabilityv1alpha1.ConditionTypeConflict)
+			Expect(conflict.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflict.Message).To(ContainSubstring("VectorSidecar claim-high"))
+
+			// The dry run plans the update of the workload it keeps
+			Expect(fakeClient.Get(ctx, highReq.NamespacedName, winner)).To(Succeed())
+			Expect(winner.Status.Plan).NotTo(BeNil())
+			Expect(winner.Status.Plan.Updates).To(Equal(int32(1)))
+			Expect(winner.Status.Plan.Actions).To(HaveLen(1))
+			Expect(winner.Status.Plan.Actions[0].Name).To(Equal("test-deployment-dryrun-claim"))
+		})
+
+		It("Should report the injection state of every target", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-targets", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			newDeployment := func(name string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{"observability": "vector-targets"},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+				}
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-targets",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-targets"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-targets"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, newDeployment("targets-healthy"), newDeployment("targets-broken"), vectorSidecar).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   &failingPatchClient{Client: fakeClient, name: "targets-broken", err: errors.New("admission webhook denied the request")},
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-targets", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(2))
+			Expect(updated.Status.OutOfDateTargets).To(Equal(int32(1)))
+
+			broken, healthy := updated.Status.Targets[0], updated.Status.Targets[1]
+			Expect(broken.Name).To(Equal("targets-broken"))
+			Expect(broken.Phase).To(Equal(observabilityv1alpha1.TargetPhaseFailed))
+			Expect(broken.LastError).To(ContainSubstring("admission webhook denied the request"))
+			Expect(broken.AppliedHash).To(BeEmpty())
+			Expect(broken.DesiredHash).To(Equal(updated.Status.InjectedHash))
+
+			Expect(healthy.Name).To(Equal("targets-healthy"))
+			Expect(healthy.Kind).To(Equal(observabilityv1alpha1.WorkloadKindDeployment))
+			Expect(healthy.Phase).To(Equal(observabilityv1alpha1.TargetPhaseInjected))
+			Expect(healthy.AppliedHash).To(Equal(healthy.DesiredHash))
+			Expect(healthy.LastError).To(BeEmpty())
+
+			// Disabling strips the injected target and keeps reporting it as Removed
+			updated.Spec.Enabled = false
+			Expect(fakeClient.Update(ctx, updated)).To(Succeed())
+			for i := 0; i < 2; i++ {
+				_, err = reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			Expect(updated.Status.Targets[0].Name).To(Equal("targets-healthy"))
+			Expect(updated.Status.Targets[0].Phase).To(Equal(observabilityv1alpha1.TargetPhaseRemoved))
+			Expect(updated.Status.OutOfDateTargets).To(BeZero())
+		})
+
+		It("Should write the status only when it changes and retry conflicts", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-status", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "status-app",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-status"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "status-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "status-app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-status",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-status"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-status"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			statusClient := &conflictingStatusClient{
+				Client:    newFakeClientBuilder(s).WithObjects(configMap, deployment, vectorSidecar).Build(),
+				conflicts: 1,
+			}
+			reconciler := &VectorSidecarReconciler{
+				Client:   statusClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(20),
+			}
+
+			// A conflicting status write is retried against the latest object
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-status", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusClient.patches).To(Equal(2))
+
+			written := &observabilityv1alpha1.VectorSidecar{}
+			Expect(statusClient.Get(ctx, req.NamespacedName, written)).To(Succeed())
+			Expect(written.Status.InjectedDeployments).To(Equal(int32(1)))
+			Expect(written.Status.LastUpdateTime.IsZero()).To(BeFalse())
+
+			// A resync that changes nothing leaves the stored object alone
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusClient.patches).To(Equal(2))
+			unchanged := &observabilityv1alpha1.VectorSidecar{}
+			Expect(statusClient.Get(ctx, req.NamespacedName, unchanged)).To(Succeed())
+			Expect(unchanged.ResourceVersion).To(Equal(written.ResourceVersion))
+			Expect(unchanged.Status.LastUpdateTime).To(Equal(written.Status.LastUpdateTime))
+
+			// A real change is written and stamped
+			current := &appsv1.Deployment{}
+			Expect(statusClient.Get(ctx, types.NamespacedName{Name: "status-app", Namespace: "default"}, current)).To(Succeed())
+			current.Labels["observability"] = "vector-other"
+			Expect(statusClient.Update(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusClient.patches).To(Equal(3))
+			Expect(statusClient.Get(ctx, req.NamespacedName, unchanged)).To(Succeed())
+			Expect(unchanged.Status.MatchedDeployments).To(BeZero())
+		})
+
+		It("Should requeue on the resync interval and throttle workload writes", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-resync", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "resync-app",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-resync"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "resync-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "resync-app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-resync",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-resync"},
+					},
+					ResyncInterval: &metav1.Duration{Duration: 10 * time.Second},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-resync"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			limiter := &countingRateLimiter{}
+			reconciler := &VectorSidecarReconciler{
+				Client:               newFakeClientBuilder(s).WithObjects(configMap, deployment, vectorSidecar).Build(),
+				Scheme:               s,
+				Recorder:             record.NewFakeRecorder(20),
+				WorkloadWriteLimiter: limiter,
+			}
+
+			// The injection waits for the write limit and requeues on the resync interval
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-resync", Namespace: "default"}}
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+			Expect(limiter.waits).To(BeNumerically(">=", 1))
+
+			// A write the limiter refuses fails the reconcile without touching the workload
+			limiter.err = context.DeadlineExceeded
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(reconciler.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			current.Spec.Sidecar.Image = "timberio/vector:0.36.0"
+			Expect(reconciler.Update(ctx, current)).To(Succeed())
+			_, _ = reconciler.Reconcile(ctx, req)
+			injected := &appsv1.Deployment{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: "resync-app", Namespace: "default"}, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers[1].Image).To(Equal("timberio/vector:0.35.0"))
+
+			// The retry backoff grows from the base delay up to the maximum
+			rateLimiter := ControllerOptions{BackoffBaseDelay: time.Second, BackoffMaxDelay: 4 * time.Second}.controllerOptions().RateLimiter
+			Expect(rateLimiter).NotTo(BeNil())
+			var delays []time.Duration
+			for i := 0; i < 4; i++ {
+				delays = append(delays, rateLimiter.When(req))
+			}
+			Expect(delays).To(Equal([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}))
+			rateLimiter.Forget(req)
+			Expect(rateLimiter.When(req)).To(Equal(time.Second))
+			Expect(ControllerOptions{MaxConcurrentReconciles: 4}.controllerOptions().RateLimiter).To(BeNil())
+		})
+
+		It("Should apply injected fields under the operator's field manager", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-apply", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-apply",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-apply"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "apply"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "apply"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							Volumes: []corev1.Volume{{
+								Name:         "app-data",
+								VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+							}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-apply",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-apply"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-apply"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			recorder := &applyRecordingClient{Client: fakeClient}
+			reconciler := &VectorSidecarReconciler{
+				Client:   recorder,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-apply", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The intent claims the sidecar and its volume, never the application's fields
+			Expect(recorder.applied).To(HaveLen(1))
+			Expect(recorder.fieldManagers).To(Equal([]string{FieldManager}))
+			intent := recorder.applied[0]
+			Expect(intent.GetAnnotations()).To(HaveKeyWithValue(AnnotationVectorSidecarName, "test-vectorsidecar-apply"))
+			containers, _, _ := unstructured.NestedSlice(intent.Object, "spec", "template", "spec", "containers")
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0]).To(HaveKeyWithValue("name", "vector"))
+			volumes, _, _ := unstructured.NestedSlice(intent.Object, "spec", "template", "spec", "volumes")
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0]).To(HaveKeyWithValue("name", VectorConfigVolumeName))
+
+			updated := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-deployment-apply", Namespace: "default"}, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(updated.Spec.Template.Spec.Containers[0].Name).To(Equal("app"))
+			Expect(updated.Spec.Template.Spec.Containers[1].Name).To(Equal("vector"))
+			Expect(updated.Spec.Template.Spec.Volumes).To(HaveLen(2))
+
+			// Removal applies an intent without fields, releasing everything the operator owned
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			current.Spec.Enabled = false
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recorder.applied).To(HaveLen(2))
+			Expect(recorder.fieldManagers).To(Equal([]string{FieldManager, FieldManager}))
+			Expect(recorder.applied[1].GetAnnotations()).To(BeEmpty())
+			_, found, _ := unstructured.NestedFieldNoCopy(recorder.applied[1].Object, "spec")
+			Expect(found).To(BeFalse())
+
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-deployment-apply", Namespace: "default"}, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(updated.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(updated.Spec.Template.Spec.Volumes[0].Name).To(Equal("app-data"))
+			Expect(updated.Annotations).NotTo(HaveKey(AnnotationInjected))
+		})
+
+		It("Should restore the original pod template from the injection manifest", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-manifest", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			sharedVolume := corev1.Volume{
+				Name:         "shared",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-manifest",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-manifest"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "manifest"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      map[string]string{"app": "manifest"},
+							Annotations: map[string]string{"team": "payments"},
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							Volumes:    []corev1.Volume{sharedVolume},
+						},
+					},
+				},
+			}
+			original := deployment.Spec.Template.DeepCopy()
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-manifest",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-manifest"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Name:  "log-shipper",
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-manifest"},
+						},
+					},
+					InitContainers: []corev1.Container{{Name: "setup", Image: "busybox:latest"}},
+					Volumes: []corev1.Volume{
+						sharedVolume,
+						{Name: "buffer", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-manifest", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-manifest", Namespace: "default"}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The volume the workload already defined is not recorded as injected
+			updated := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			manifest := &injectionManifest{}
+			Expect(json.Unmarshal([]byte(updated.Annotations[AnnotationInjectionManifest]), manifest)).To(Succeed())
+			Expect(manifest.Container).To(Equal("log-shipper"))
+			Expect(manifest.InitContainers).To(Equal([]string{"setup"}))
+			Expect(manifest.Volumes).To(ConsistOf(VectorConfigVolumeName, "buffer"))
+			Expect(manifest.PodAnnotations).To(ConsistOf(AnnotationInjectedHash, AnnotationConfigHash))
+
+			// Renaming the sidecar drops the container injected under the old name
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			current.Spec.Sidecar.Name = "vector-agent"
+			current.Spec.Sidecar.Image = "timberio/vector:0.36.0"
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(updated.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(updated.Spec.Template.Spec.Containers[0].Name).To(Equal("app"))
+			Expect(updated.Spec.Template.Spec.Containers[1].Name).To(Equal("vector-agent"))
+
+			// Deleting the VectorSidecar restores the pod template exactly
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(fakeClient.Delete(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(updated.Spec.Template, *original)).To(BeTrue(),
+				"pod template not restored: %+v", updated.Spec.Template)
+			Expect(updated.Annotations).NotTo(HaveKey(AnnotationInjectionManifest))
+			Expect(updated.Annotations).NotTo(HaveKey(AnnotationInjected))
+		})
+
+		It("Should export injection, drift and removal metrics", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-metrics", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-metrics",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-metrics"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "metrics"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "metrics"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-metrics",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-metrics"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-metrics"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-metrics", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-metrics", Namespace: "default"}
+			targets := func(phase observabilityv1alpha1.TargetPhase) float64 {
+				return testutil.ToFloat64(targetsGauge.WithLabelValues("default", "test-vectorsidecar-metrics", string(phase)))
+			}
+			injections := testutil.ToFloat64(injectionsTotal.WithLabelValues(injectionResultSuccess))
+			drifts := testutil.ToFloat64(driftDetectedTotal)
+			removals := testutil.ToFloat64(removalsTotal)
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(injectionsTotal.WithLabelValues(injectionResultSuccess))).To(Equal(injections + 1))
+			Expect(targets(observabilityv1alpha1.TargetPhaseInjected)).To(Equal(1.0))
+			Expect(targets(observabilityv1alpha1.TargetPhaseFailed)).To(BeZero())
+
+			// An unchanged workload is not written again
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(injectionsTotal.WithLabelValues(injectionResultSuccess))).To(Equal(injections + 1))
+			Expect(testutil.ToFloat64(driftDetectedTotal)).To(Equal(drifts))
+
+			// Each drift re-applied by the reconciler is counted
+			updated := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+			updated.Spec.Template.Spec.Containers = updated.Spec.Template.Spec.Containers[:1]
+			Expect(fakeClient.Update(ctx, updated)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(driftDetectedTotal)).To(Equal(drifts + 1))
+
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			current.Spec.Enabled = false
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(removalsTotal)).To(Equal(removals + 1))
+			Expect(targets(observabilityv1alpha1.TargetPhaseInjected)).To(BeZero())
+			Expect(targets(observabilityv1alpha1.TargetPhaseRemoved)).To(Equal(1.0))
+			Expect(testutil.CollectAndCount(reconcileDuration)).To(BeNumerically(">", 0))
+		})
+
+		It("Should roll injection changes out in batches", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-rollout", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			available := appsv1.DeploymentStatus{
+				Replicas:          1,
+				UpdatedReplicas:   1,
+				AvailableReplicas: 1,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+				},
+			}
+			deployment := func(name string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{"observability": "vector-rollout"},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+					Status: available,
+				}
+			}
+			maxBatchSize := intstr.FromInt(1)
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-rollout",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-rollout"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-rollout"},
+						},
+					},
+					RolloutStrategy: &observabilityv1alpha1.RolloutStrategy{
+						MaxBatchSize:        &maxBatchSize,
+						PauseBetweenBatches: &metav1.Duration{Duration: time.Hour},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment("rollout-a"), deployment("rollout-b"), deployment("rollout-c"), vectorSidecar).
+				Build()
+			newReconciler := func() *VectorSidecarReconciler {
+				return &VectorSidecarReconciler{
+					Client:   fakeClient,
+					Scheme:   s,
+					Recorder: record.NewFakeRecorder(20),
+				}
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-rollout", Namespace: "default"}}
+			phases := func() map[string]observabilityv1alpha1.TargetPhase {
+				current := &observabilityv1alpha1.VectorSidecar{}
+				Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+				phases := map[string]observabilityv1alpha1.TargetPhase{}
+				for _, target := range current.Status.Targets {
+					phases[target.Name] = target.Phase
+				}
+				return phases
+			}
+			setDeploymentStatus := func(name string, status appsv1.DeploymentStatus) {
+				updated := &appsv1.Deployment{}
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, updated)).To(Succeed())
+				updated.Status = status
+				Expect(fakeClient.Update(ctx, updated)).To(Succeed())
+			}
+
+			// The first batch holds a single workload; the others wait
+			result, err := newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(rolloutPollInterval))
+			Expect(phases()).To(Equal(map[string]observabilityv1alpha1.TargetPhase{
+				"rollout-a": observabilityv1alpha1.TargetPhaseInjected,
+				"rollout-b": observabilityv1alpha1.TargetPhasePending,
+				"rollout-c": observabilityv1alpha1.TargetPhasePending,
+			}))
+
+			// The next batch waits for the first to become available again
+			setDeploymentStatus("rollout-a", appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1})
+			result, err = newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(rolloutPollInterval))
+			Expect(phases()["rollout-b"]).To(Equal(observabilityv1alpha1.TargetPhasePending))
+
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.Rollout.CurrentBatch).To(Equal([]string{"Deployment/rollout-a"}))
+			progressing := findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeProgressing)
+			Expect(progressing).NotTo(BeNil())
+			Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+			Expect(progressing.Reason).To(Equal("BatchInProgress"))
+
+			// Once available, the pause between batches holds the next one back
+			setDeploymentStatus("rollout-a", available)
+			result, err = newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phases()["rollout-b"]).To(Equal(observabilityv1alpha1.TargetPhasePending))
+
+			// Progress is read back from status, so a restarted operator resumes after the pause
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.Rollout.CurrentBatch).To(BeEmpty())
+			paused := findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeProgressing)
+			Expect(paused.Reason).To(Equal("BatchPaused"))
+			nextBatch := current.Status.Rollout.LastBatchCompletionTime.Add(time.Hour).UTC().Format(time.RFC3339)
+			Expect(paused.Message).To(Equal("Next batch starts at " + nextBatch + "; 1/3 workloads updated"))
+			Expect(current.Status.Rollout.UpdatedTargets).To(Equal(int32(1)))
+			Expect(current.Status.Rollout.TotalTargets).To(Equal(int32(3)))
+			elapsed := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			current.Status.Rollout.LastBatchCompletionTime = &elapsed
+			Expect(fakeClient.Status().Update(ctx, current)).To(Succeed())
+			_, err = newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phases()).To(Equal(map[string]observabilityv1alpha1.TargetPhase{
+				"rollout-a": observabilityv1alpha1.TargetPhaseInjected,
+				"rollout-b": observabilityv1alpha1.TargetPhaseInjected,
+				"rollout-c": observabilityv1alpha1.TargetPhasePending,
+			}))
+
+			// A percentage without pause rolls the remaining workloads out back to back
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			percentage := intstr.FromString("50%")
+			current.Spec.RolloutStrategy = &observabilityv1alpha1.RolloutStrategy{MaxBatchSize: &percentage}
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			_, err = newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phases()["rollout-c"]).To(Equal(observabilityv1alpha1.TargetPhaseInjected))
+
+			result, err = newReconciler().Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.Rollout.UpdatedTargets).To(Equal(int32(3)))
+			progressing = findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeProgressing)
+			Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+			Expect(progressing.Reason).To(Equal("RolloutComplete"))
+		})
+
+		It("Should keep a batch open until its workloads carry the new injection", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-gated", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := func(name string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{"observability": "vector-gated"},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+					Status: appsv1.DeploymentStatus{
+						Replicas:          1,
+						UpdatedReplicas:   1,
+						AvailableReplicas: 1,
+						Conditions: []appsv1.DeploymentCondition{
+							{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+						},
+					},
+				}
+			}
+			maxBatchSize := intstr.FromInt(1)
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-gated",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-gated"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-gated"},
+						},
+					},
+					RolloutStrategy: &observabilityv1alpha1.RolloutStrategy{MaxBatchSize: &maxBatchSize},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment("gated-a"), deployment("gated-b"), vectorSidecar).
+				Build()
+			failing := &failingPatchClient{Client: fakeClient, name: "gated-a", err: errors.New("admission webhook denied the request")}
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-gated", Namespace: "default"}}
+
+			// The first workload is available but never receives the injection, so the batch stays open
+			for i := 0; i < 2; i++ {
+				reconciler := &VectorSidecarReconciler{Client: failing, Scheme: s, Recorder: record.NewFakeRecorder(20)}
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+
+				current := &observabilityv1alpha1.VectorSidecar{}
+				Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+				Expect(current.Status.Rollout.CurrentBatch).To(Equal([]string{"Deployment/gated-a"}))
+				Expect(current.Status.Rollout.LastBatchCompletionTime).To(BeNil())
+				phases := map[string]observabilityv1alpha1.TargetPhase{}
+				for _, target := range current.Status.Targets {
+					phases[target.Name] = target.Phase
+				}
+				Expect(phases).To(Equal(map[string]observabilityv1alpha1.TargetPhase{
+					"gated-a": observabilityv1alpha1.TargetPhaseFailed,
+					"gated-b": observabilityv1alpha1.TargetPhasePending,
+				}))
+			}
+
+			// Once the injection goes through, the batch completes and the next one starts
+			reconciler := &VectorSidecarReconciler{Client: fakeClient, Scheme: s, Recorder: record.NewFakeRecorder(20)}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.Rollout.CurrentBatch).To(Equal([]string{"Deployment/gated-b"}))
+			Expect(current.Status.Rollout.UpdatedTargets).To(Equal(int32(1)))
+		})
+
+		It("Should roll back an injection that leaves workloads unhealthy", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-rollback", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-rollback",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-rollback"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "rollback"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "rollback"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+				Status: appsv1.DeploymentStatus{
+					Replicas:          1,
+					UpdatedReplicas:   1,
+					AvailableReplicas: 1,
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-rollback",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-rollback"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-rollback"},
+						},
+					},
+					RollbackPolicy: &observabilityv1alpha1.RollbackPolicy{},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			recorder := record.NewFakeRecorder(20)
+			apiReader := &listRecordingClient{Client: fakeClient}
+			reconciler := &VectorSidecarReconciler{
+				Client:    fakeClient,
+				Scheme:    s,
+				Recorder:  recorder,
+				APIReader: apiReader,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-rollback", Namespace: "default"}}
+			deploymentKey := types.NamespacedName{Name: "test-deployment-rollback", Namespace: "default"}
+			vectorImage := func() string {
+				updated := &appsv1.Deployment{}
+				Expect(fakeClient.Get(ctx, deploymentKey, updated)).To(Succeed())
+				for _, container := range updated.Spec.Template.Spec.Containers {
+					if container.Name == "vector" {
+						return container.Image
+					}
+				}
+				return ""
+			}
+			setImage := func(image string) {
+				current := &observabilityv1alpha1.VectorSidecar{}
+				Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+				current.Spec.Sidecar.Image = image
+				Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			}
+
+			// The injection becomes known-good once the workload rolled it out healthily
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			knownGoodHash := current.Status.InjectedHash
+			Expect(current.Status.LastKnownGoodHash).To(Equal(knownGoodHash))
+			revision := &appsv1.ControllerRevision{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{
+				Name:      knownGoodRevisionName(current, knownGoodHash),
+				Namespace: "default",
+			}, revision)).To(Succeed())
+			Expect(revision.OwnerReferences).To(HaveLen(1))
+			Expect(revision.Labels).To(BeEmpty())
+			Expect(revision.Annotations).To(HaveKeyWithValue(AnnotationVectorSidecarName, "test-vectorsidecar-rollback"))
+
+			// A new image is rolled out and its health watched
+			setImage("timberio/vector:broken")
+			result, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(rolloutPollInterval))
+			Expect(vectorImage()).To(Equal("timberio/vector:broken"))
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			failingHash := current.Status.InjectedHash
+
+			// A Vector container in CrashLoopBackOff exceeds the default budget of zero
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-deployment-rollback-abc12",
+					Namespace:   "default",
+					Labels:      map[string]string{"app": "rollback"},
+					Annotations: map[string]string{AnnotationInjectedHash: failingHash},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "app", Ready: true},
+						{Name: "vector", State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						}},
+					},
+				},
+			}
+			Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vectorImage()).To(Equal("timberio/vector:0.35.0"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RolledBack")))
+
+			// Pods are read through the API reader rather than the cache
+			Expect(apiReader.lists).NotTo(BeEmpty())
+			for _, list := range apiReader.lists {
+				Expect(list).To(BeAssignableToTypeOf(&corev1.PodList{}))
+			}
+
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.FailedHash).To(Equal(failingHash))
+			degraded := findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeDegraded)
+			Expect(degraded).NotTo(BeNil())
+			Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degraded.Reason).To(Equal("RolledBack"))
+			Expect(degraded.Message).To(ContainSubstring(failingHash))
+			Expect(degraded.Message).To(ContainSubstring("Deployment/test-deployment-rollback (CrashLoopBackOff)"))
+			ready := findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeReady)
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("RolledBack"))
+			Expect(current.Status.Targets).To(HaveLen(1))
+			Expect(current.Status.Targets[0].Phase).To(Equal(observabilityv1alpha1.TargetPhaseFailed))
+			Expect(current.Status.Targets[0].AppliedHash).To(Equal(knownGoodHash))
+			Expect(current.Status.Targets[0].DesiredHash).To(Equal(failingHash))
+
+			// The failed injection is not retried while the spec still renders it
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vectorImage()).To(Equal("timberio/vector:0.35.0"))
+
+			// A new spec clears the failure and is rolled out again
+			setImage("timberio/vector:0.36.0")
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vectorImage()).To(Equal("timberio/vector:0.36.0"))
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.FailedHash).To(BeEmpty())
+			degraded = findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeDegraded)
+			Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("Should publish the planned changes in dry run without changing workloads", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-dryrun", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := func(name string, replicas int32) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{"observability": "vector-dryrun"},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Replicas: int32Ptr(replicas),
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+				}
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-dryrun",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-dryrun"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-dryrun"},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment("dryrun-stale", 2), vectorSidecar).
+				Build()
+			recorder := record.NewFakeRecorder(20)
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: recorder,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-dryrun", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Switch to dry run with a new image and larger requests, then select another workload
+			Expect(fakeClient.Create(ctx, deployment("dryrun-new", 3))).To(Succeed())
+			current := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			staleHash := current.Status.InjectedHash
+			current.Spec.Mode = observabilityv1alpha1.ReconcileModeDryRun
+			current.Spec.Sidecar.Image = "timberio/vector:0.36.0"
+			current.Spec.Sidecar.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("200m")
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+
+			before := &appsv1.DeploymentList{}
+			Expect(fakeClient.List(ctx, before, client.InNamespace("default"))).To(Succeed())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			after := &appsv1.DeploymentList{}
+			Expect(fakeClient.List(ctx, after, client.InNamespace("default"))).To(Succeed())
+			Expect(after.Items).To(Equal(before.Items))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("DryRunPlanned"),
+				ContainSubstring("would inject 1, update 1 and strip 0 workloads"),
+			)))
+
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			plan := current.Status.Plan
+			Expect(plan).NotTo(BeNil())
+			Expect(plan.Hash).NotTo(Equal(staleHash))
+			Expect(plan.Injections).To(Equal(int32(1)))
+			Expect(plan.Updates).To(Equal(int32(1)))
+			Expect(plan.PodRestarts).To(Equal(int32(5)))
+			Expect(plan.CPURequests.Cmp(resource.MustParse("800m"))).To(BeZero())
+			Expect(plan.MemoryRequests.Cmp(resource.MustParse("192Mi"))).To(BeZero())
+			Expect(plan.Actions).To(HaveLen(2))
+			Expect(plan.Actions[0].Name).To(Equal("dryrun-new"))
+			Expect(plan.Actions[0].Action).To(Equal(observabilityv1alpha1.PlannedActionInject))
+			Expect(plan.Actions[0].OldHash).To(BeEmpty())
+			Expect(plan.Actions[0].NewHash).To(Equal(plan.Hash))
+			Expect(plan.Actions[0].PodRestarts).To(Equal(int32(3)))
+			Expect(plan.Actions[1].Name).To(Equal("dryrun-stale"))
+			Expect(plan.Actions[1].Action).To(Equal(observabilityv1alpha1.PlannedActionUpdate))
+			Expect(plan.Actions[1].OldHash).To(Equal(staleHash))
+			Expect(plan.Actions[1].CPURequests.Cmp(resource.MustParse("200m"))).To(BeZero())
+			Expect(plan.Actions[1].MemoryRequests.IsZero()).To(BeTrue())
+			ready := findCondition(current.Status.Conditions, observabilityv1alpha1.ConditionTypeReady)
+			Expect(ready.Reason).To(Equal("DryRun"))
+
+			// An unchanged plan is not announced again
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("DryRunPlanned")))
+
+			// Disabling plans to strip the injected workload
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			current.Spec.Enabled = false
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, req.NamespacedName, current)).To(Succeed())
+			Expect(current.Status.Plan.Removals).To(Equal(int32(1)))
+			Expect(current.Status.Plan.Actions).To(HaveLen(1))
+			Expect(current.Status.Plan.Actions[0].Action).To(Equal(observabilityv1alpha1.PlannedActionStrip))
+			Expect(current.Status.Plan.CPURequests.Cmp(resource.MustParse("-200m"))).To(BeZero())
+			Expect(current.Status.Plan.MemoryRequests.Cmp(resource.MustParse("-128Mi"))).To(BeZero())
+
+			stale := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "dryrun-stale", Namespace: "default"}, stale)).To(Succeed())
+			Expect(stale.Annotations[AnnotationInjectedHash]).To(Equal(staleHash))
+		})
+
+		It("Should strip the sidecar from workloads that stop matching after the grace period", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-unmatched", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unmatched-app",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-unmatched"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "unmatched-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "unmatched-app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-unmatched",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-unmatched"},
+					},
+					UnmatchedGracePeriod: &metav1.Duration{Duration: 10 * time.Minute},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-unmatched"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, deployment, vectorSidecar).
+				Build()
+			recorder := record.NewFakeRecorder(20)
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: recorder,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-unmatched", Namespace: "default"}}
+			deploymentName := types.NamespacedName{Name: "unmatched-app", Namespace: "default"}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Relabeling the workload reports it as Unmatched but keeps the sidecar during the grace period
+			current := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, deploymentName, current)).To(Succeed())
+			Expect(current.Spec.Template.Spec.Containers).To(HaveLen(2))
+			current.Labels["observability"] = "vector-other"
+			Expect(fakeClient.Update(ctx, current)).To(Succeed())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("WorkloadUnmatched"),
+				ContainSubstring("Deployment/unmatched-app"),
+			)))
+
+			Expect(fakeClient.Get(ctx, deploymentName, current)).To(Succeed())
+			Expect(current.Spec.Template.Spec.Containers).To(HaveLen(2))
+			updated := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.MatchedDeployments).To(BeZero())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			Expect(updated.Status.Targets[0].Phase).To(Equal(observabilityv1alpha1.TargetPhaseUnmatched))
+			Expect(updated.Status.Targets[0].AppliedHash).To(Equal(updated.Status.InjectedHash))
+			Expect(updated.Status.OutOfDateTargets).To(BeZero())
+
+			// Later passes within the grace period do not announce the workload again
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("WorkloadUnmatched")))
+
+			// Once the grace period elapsed the sidecar is stripped and the target reported as Removed
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			updated.Status.Targets[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-15 * time.Minute))
+			Expect(fakeClient.Status().Update(ctx, updated)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("SidecarRemoved")))
+
+			Expect(fakeClient.Get(ctx, deploymentName, current)).To(Succeed())
+			Expect(current.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(current.Annotations).NotTo(HaveKey(AnnotationVectorSidecarName))
+
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			Expect(updated.Status.Targets[0].Phase).To(Equal(observabilityv1alpha1.TargetPhaseRemoved))
+		})
+
+		It("Should strip or adopt workloads whose VectorSidecar was force-deleted", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-orphan", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := func(name string, labels map[string]string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+				}
+			}
+			vectorSidecar := func(name string, labels map[string]string) *observabilityv1alpha1.VectorSidecar {
+				return &observabilityv1alpha1.VectorSidecar{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       name,
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
+					},
+					Spec: observabilityv1alpha1.VectorSidecarSpec{
+						Enabled:  true,
+						Selector: metav1.LabelSelector{MatchLabels: labels},
+						Sidecar: observabilityv1alpha1.SidecarConfig{
+							Image: "timberio/vector:0.35.0",
+							Config: observabilityv1alpha1.VectorConfig{
+								ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-orphan"},
+							},
+						},
+					},
+				}
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			clusterConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterConfigMapName("orphan-cluster-gone"), Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(configMap, clusterConfigMap,
+					deployment("orphan-stripped", map[string]string{"observability": "vector-orphan"}),
+					deployment("orphan-adopted", map[string]string{"observability": "vector-orphan", "team": "heir"}),
+					deployment("orphan-cluster", nil),
+					vectorSidecar("orphan-gone", map[string]string{"observability": "vector-orphan"})).
+				Build()
+			recorder := record.NewFakeRecorder(20)
+			reconciler := &VectorSidecarReconciler{
+				Client:    fakeClient,
+				Scheme:    s,
+				Recorder:  recorder,
+				adoptions: make(chan event.GenericEvent, 1),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "orphan-gone", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// A ClusterVectorSidecar that no longer exists injected another workload
+			clusterView := clusterSidecarView(&observabilityv1alpha1.ClusterVectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{Name: "orphan-cluster-gone"},
+				Spec: observabilityv1alpha1.ClusterVectorSidecarSpec{
+					Enabled: true,
+					Sidecar: observabilityv1alpha1.SidecarConfig{Image: "timberio/vector:0.35.0"},
+				},
+			}, "default", InlineConfigKey)
+			clusterWorkload := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "orphan-cluster", Namespace: "default"}, clusterWorkload)).To(Succeed())
+			wl, err := newWorkload(clusterWorkload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.injectSidecar(ctx, clusterView, wl)).To(Succeed())
+
+			// Force-delete the VectorSidecar, leaving both workloads injected
+			gone := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, gone)).To(Succeed())
+			gone.Finalizers = nil
+			Expect(fakeClient.Update(ctx, gone)).To(Succeed())
+			Expect(fakeClient.Delete(ctx, gone)).To(Succeed())
+			Expect(fakeClient.Create(ctx, vectorSidecar("orphan-heir", map[string]string{"team": "heir"}))).To(Succeed())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			stripped := testutil.ToFloat64(orphansTotal.WithLabelValues(orphanActionStripped))
+			adopted := testutil.ToFloat64(orphansTotal.WithLabelValues(orphanActionAdopted))
+			collector := &OrphanCollector{
+				Client:   fakeClient,
+				Recorder: recorder,
+				Injector: reconciler,
+				Interval: time.Minute,
+				Policy:   OrphanPolicyAdopt,
+			}
+			Expect(collector.sweep(ctx)).To(Succeed())
+			Expect(testutil.ToFloat64(orphansTotal.WithLabelValues(orphanActionStripped))).To(Equal(stripped + 2))
+			Expect(testutil.ToFloat64(orphansTotal.WithLabelValues(orphanActionAdopted))).To(Equal(adopted + 1))
+
+			// The workload selected by another VectorSidecar is released and its heir enqueued
+			current := &appsv1.Deployment{}
+			adoptedKey := types.NamespacedName{Name: "orphan-adopted", Namespace: "default"}
+			Expect(fakeClient.Get(ctx, adoptedKey, current)).To(Succeed())
+			Expect(current.Annotations).NotTo(HaveKey(AnnotationVectorSidecarName))
+			Expect(current.Spec.Template.Spec.Containers).To(HaveLen(2))
+			var adoption event.GenericEvent
+			Expect(reconciler.adoptions).To(Receive(&adoption))
+			Expect(adoption.Object.GetName()).To(Equal("orphan-heir"))
+
+			// The heir takes the workload over in its own reconcile
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "orphan-heir", Namespace: "default"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, adoptedKey, current)).To(Succeed())
+			Expect(current.Annotations[AnnotationVectorSidecarName]).To(Equal("orphan-heir"))
+			Expect(current.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+			// The workloads nobody selects lose the sidecar
+			for _, name := range []string{"orphan-stripped", "orphan-cluster"} {
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, current)).To(Succeed())
+				Expect(current.Annotations).NotTo(HaveKey(AnnotationVectorSidecarName))
+				Expect(current.Annotations).NotTo(HaveKey(AnnotationClusterVectorSidecarName))
+				Expect(current.Spec.Template.Spec.Containers).To(HaveLen(1), "workload %s", name)
+				Expect(current.Spec.Template.Spec.Volumes).To(BeEmpty(), "workload %s", name)
+			}
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(ContainSubstring("OrphanAdopted")))
+			Expect(events).To(ContainElement(And(ContainSubstring("OrphanStripped"), ContainSubstring("VectorSidecar default/orphan-gone"))))
+			Expect(events).To(ContainElement(And(ContainSubstring("OrphanStripped"), ContainSubstring("ClusterVectorSidecar orphan-cluster-gone"))))
+
+			// A second sweep finds nothing left to collect
+			Expect(collector.sweep(ctx)).To(Succeed())
+			Expect(testutil.ToFloat64(orphansTotal.WithLabelValues(orphanActionStripped))).To(Equal(stripped + 2))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("Should inject ClusterVectorSidecars into the selected namespaces", func() {
+			namespace := func(name string, labels map[string]string) *corev1.Namespace {
+				return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+			}
+			deployment := func(namespace string) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-deployment-cluster",
+						Namespace: namespace,
+						Labels:    map[string]string{"observability": "vector-cluster"},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cluster"}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "cluster"}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+							},
+						},
+					},
+				}
+			}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config", Namespace: "vector-system"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			clusterSidecar := &observabilityv1alpha1.ClusterVectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-clustervectorsidecar",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.ClusterVectorSidecarSpec{
+					Enabled: true,
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "enabled"},
+					},
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-cluster"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config", Namespace: "vector-system"},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-vectorsidecar-override", Namespace: "team-b"},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-cluster"},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(
+					namespace("vector-system", nil),
+					namespace("team-a", map[string]string{"observability": "enabled"}),
+					namespace("team-b", map[string]string{"observability": "enabled"}),
+					namespace("team-c", nil),
+					deployment("team-a"), deployment("team-b"), deployment("team-c"),
+					configMap, clusterSidecar, vectorSidecar,
+				).
+				Build()
+			reconciler := &ClusterVectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+				Injector: &VectorSidecarReconciler{Client: fakeClient, Scheme: s, Recorder: record.NewFakeRecorder(10)},
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-clustervectorsidecar"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Only the selected namespace without a namespaced VectorSidecar is injected
+			injected := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-deployment-cluster", Namespace: "team-a"}, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(injected.Annotations[AnnotationClusterVectorSidecarName]).To(Equal("test-clustervectorsidecar"))
+			Expect(injected.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("test-clustervectorsidecar-cluster-config"))
+
+			copied := &corev1.ConfigMap{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-clustervectorsidecar-cluster-config", Namespace: "team-a"}, copied)).To(Succeed())
+			Expect(copied.Data).To(HaveKeyWithValue("vector.yaml", validVectorConfig))
+
+			for _, ns := range []string{"team-b", "team-c"} {
+				skipped := &appsv1.Deployment{}
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-deployment-cluster", Namespace: ns}, skipped)).To(Succeed())
+				Expect(skipped.Spec.Template.Spec.Containers).To(HaveLen(1), "namespace %s", ns)
+			}
+
+			updatedCluster := &observabilityv1alpha1.ClusterVectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.MatchedNamespaces).To(Equal(int32(2)))
+			Expect(updatedCluster.Status.InjectedWorkloads).To(Equal(int32(1)))
+			Expect(updatedCluster.Status.OverriddenWorkloads).To(Equal(int32(1)))
+
+			// Deselecting the namespace strips the sidecar and the copied configuration
+			teamA := &corev1.Namespace{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "team-a"}, teamA)).To(Succeed())
+			teamA.Labels = nil
+			Expect(fakeClient.Update(ctx, teamA)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-deployment-cluster", Namespace: "team-a"}, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(injected.Annotations).NotTo(HaveKey(AnnotationClusterVectorSidecarName))
+			err = fakeClient.Get(ctx, types.NamespacedName{Name: "test-clustervectorsidecar-cluster-config", Namespace: "team-a"}, copied)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should strip ClusterVectorSidecar workloads that stop matching in selected namespaces", func() {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-cluster-unmatched",
+					Namespace: "team-d",
+					Labels:    map[string]string{"observability": "vector-cluster-unmatched"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cluster-unmatched"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "cluster-unmatched"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}},
+						},
+					},
+				},
+			}
+			clusterSidecar := &observabilityv1alpha1.ClusterVectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-clustervectorsidecar-unmatched",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.ClusterVectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-cluster-unmatched"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image:  "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{Inline: validVectorConfig},
+					},
+					UnmatchedGracePeriod: &metav1.Duration{Duration: time.Hour},
+					ResyncInterval:       &metav1.Duration{Duration: 2 * time.Hour},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).
+				WithObjects(
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-d"}},
+					deployment, clusterSidecar,
+				).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ClusterVectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+				Injector: &VectorSidecarReconciler{Client: fakeClient, Scheme: s, Recorder: recorder},
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterSidecar.Name}}
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Hour))
+
+			workloadKey := types.NamespacedName{Name: deployment.Name, Namespace: "team-d"}
+			injected := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, workloadKey, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+			// The namespace stays selected, but the workload stops matching the selector
+			injected.Labels = nil
+			Expect(fakeClient.Update(ctx, injected)).To(Succeed())
+			result, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+			Expect(recorder.Events).To(Receive(ContainSubstring("WorkloadUnmatched")))
+
+			Expect(fakeClient.Get(ctx, workloadKey, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+			updatedCluster := &observabilityv1alpha1.ClusterVectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.UnmatchedTargets).To(HaveLen(1))
+			Expect(updatedCluster.Status.UnmatchedTargets[0].Namespace).To(Equal("team-d"))
+			Expect(updatedCluster.Status.UnmatchedTargets[0].Phase).To(Equal(observabilityv1alpha1.TargetPhaseUnmatched))
+
+			// Once the grace period has elapsed the sidecar is removed
+			updatedCluster.Status.UnmatchedTargets[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			Expect(fakeClient.Status().Update(ctx, updatedCluster)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("SidecarRemoved")))
+
+			Expect(fakeClient.Get(ctx, workloadKey, injected)).To(Succeed())
+			Expect(injected.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(injected.Annotations).NotTo(HaveKey(AnnotationClusterVectorSidecarName))
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.UnmatchedTargets).To(BeEmpty())
+		})
+
+		It("Should validate the structure of the Vector configuration", func() {
+			Expect(validateVectorConfig("vector.yaml", validVectorConfig)).To(Succeed())
+			Expect(validateVectorConfig("vector.toml", `
+[sources.logs]
+type = "file"
+include = ["/var/log/*.log"]
+
+[transforms.route]
+type = "route"
+inputs = ["logs"]
+route.errors = '.level == "error"'
+
+[sinks.out]
+type = "console"
+inputs = ["route.errors", "log*"]
+encoding.codec = "json"
+`)).To(Succeed())
+			Expect(validateVectorConfig("vector.json",
+				`{"sources":{"logs":{"type":"stdin"}},"sinks":{"out":{"type":"blackhole","inputs":["logs"]}}}`)).To(Succeed())
+
+			invalid := map[string]string{
+				"failed to parse":                "sources: [",
+				"no sinks":                       "sources:\n  logs:\n    type: stdin\n",
+				`source "logs" has no type`:      "sources:\n  logs: {}\nsinks:\n  out:\n    type: blackhole\n    inputs: [logs]\n",
+				`sink "out" has input "missing"`: strings.Replace(validVectorConfig, `inputs: ["logs"]`, `inputs: ["missing"]`, 1),
+				`transform "a" is part of an input cycle`: validVectorConfig + `transforms:
+  a:
+    type: remap
+    inputs: ["b"]
+  b:
+    type: remap
+    inputs: ["a"]
+`,
+			}
+			for message, content := range invalid {
+				err := validateVectorConfig("vector.yaml", content)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(message))
+			}
+
+			// TOML keys are mounted with their extension so Vector picks the same parser
+			Expect(vectorConfigFileName("vector.toml")).To(Equal("vector.toml"))
+			Expect(vectorConfigFileName("config")).To(Equal("vector.yaml"))
+
+			// Reconciling an invalid config reports the offending component on ConfigValid
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-badconfig",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-badconfig"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							Inline: invalid[`sink "out" has input "missing"`],
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).WithObjects(vectorSidecar).Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-badconfig", Namespace: "default"}}
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			updatedVS := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updatedVS)).To(Succeed())
+			configCondition := findCondition(updatedVS.Status.Conditions, observabilityv1alpha1.ConditionTypeConfigValid)
+			Expect(configCondition).NotTo(BeNil())
+			Expect(configCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(configCondition.Message).To(ContainSubstring(`sink "out"`))
+		})
+
+		It("Should reject invalid VectorSidecars at admission", func() {
+			valid := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-validation",
+					Namespace: "default",
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "test"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Name:  "vector",
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{
+								Name: "vector-config",
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "varlog", MountPath: "/var/log"},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("500m"),
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "varlog", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+				},
+			}
+			Expect(valid.ValidateCreate()).To(Succeed())
+			Expect(valid.ValidateUpdate(valid.DeepCopy())).To(Succeed())
+
+			invalid := map[string]func(vs *observabilityv1alpha1.VectorSidecar){
+				"spec.sidecar.config.inline": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Config.Inline = "sources: {}"
+				},
+				"spec.selector": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Selector = metav1.LabelSelector{}
+				},
+				"spec.sidecar.name": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Name = "Vector_Agent"
+				},
+				"spec.volumes[1].name": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Volumes = append(vs.Spec.Volumes, corev1.Volume{Name: observabilityv1alpha1.LifecycleVolumeName})
+				},
+				"spec.initContainers[0].name": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.InitContainers = []corev1.Container{{Name: "vector", Image: "busybox:latest"}}
+				},
+				"spec.sidecar.volumeMounts[0].name": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Volumes = nil
+				},
+				"spec.sidecar.resources.requests[cpu]": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("2")
+				},
+				"spec.sidecar.config.configMapRef.namespace": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Config.ConfigMapRef.Namespace = "vector-system"
+				},
+				"spec.resyncInterval": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.ResyncInterval = &metav1.Duration{}
+				},
+			}
+			for fieldPath, mutate := range invalid {
+				vs := valid.DeepCopy()
+				mutate(vs)
+				err := vs.ValidateCreate()
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected %s to be rejected", fieldPath)
+				Expect(err.Error()).To(ContainSubstring(fieldPath))
+			}
+
+			// Volume names are reserved for volumes only
+			renamed := valid.DeepCopy()
+			renamed.Spec.Sidecar.Name = observabilityv1alpha1.VectorConfigVolumeName
+			Expect(renamed.ValidateCreate()).To(Succeed())
+		})
+
+		It("Should reject invalid ClusterVectorSidecars at admission", func() {
+			valid := &observabilityv1alpha1.ClusterVectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-validation"},
+				Spec: observabilityv1alpha1.ClusterVectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "test"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config", Namespace: "vector-system"},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "varlog", MountPath: "/var/log"},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "varlog", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+				},
+			}
+			Expect(valid.ValidateCreate()).To(Succeed())
+			Expect(valid.ValidateUpdate(valid.DeepCopy())).To(Succeed())
+
+			invalid := map[string]func(cvs *observabilityv1alpha1.ClusterVectorSidecar){
+				"spec.selector": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.Selector = metav1.LabelSelector{}
+				},
+				"spec.sidecar.config.configMapRef.namespace": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.Sidecar.Config.ConfigMapRef.Namespace = ""
+				},
+				"spec.volumes[1].name": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.Volumes = append(cvs.Spec.Volumes, corev1.Volume{Name: observabilityv1alpha1.VectorConfigVolumeName})
+				},
+				"spec.sidecar.volumeMounts[0].name": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.Volumes = nil
+				},
+				"spec.unmatchedGracePeriod": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.UnmatchedGracePeriod = &metav1.Duration{Duration: -time.Minute}
+				},
+				"spec.resyncInterval": func(cvs *observabilityv1alpha1.ClusterVectorSidecar) {
+					cvs.Spec.ResyncInterval = &metav1.Duration{}
+				},
+			}
+			for fieldPath, mutate := range invalid {
+				cvs := valid.DeepCopy()
+				mutate(cvs)
+				err := cvs.ValidateCreate()
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected %s to be rejected", fieldPath)
+				Expect(err.Error()).To(ContainSubstring(fieldPath))
+			}
+		})
+
+		It("Should not replace workload containers named like injected ones", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vector-config-clash", Namespace: "default"},
+				Data:       map[string]string{"vector.yaml": validVectorConfig},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "clash-app",
+					Namespace: "default",
+					Labels:    map[string]string{"observability": "vector-clash"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "clash-app"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "clash-app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "app", Image: "nginx:latest"},
+								{Name: "vector", Image: "example.com/own-vector:1.0"},
+							},
+						},
+					},
+				},
+			}
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-vectorsidecar-clash",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Enabled: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"observability": "vector-clash"},
+					},
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config-clash"},
+						},
+					},
+				},
+			}
+
+			s := scheme.Scheme
+			_ = observabilityv1alpha1.AddToScheme(s)
+			fakeClient := newFakeClientBuilder(s).WithObjects(configMap, deployment, vectorSidecar).Build()
+			reconciler := &VectorSidecarReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-vectorsidecar-clash", Namespace: "default"}}
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			unchanged := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), unchanged)).To(Succeed())
+			Expect(unchanged.Spec.Template.Spec.Containers).To(Equal(deployment.Spec.Template.Spec.Containers))
+			Expect(unchanged.Annotations).NotTo(HaveKey(AnnotationInjected))
+
+			updated := &observabilityv1alpha1.VectorSidecar{}
+			Expect(fakeClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(ConsistOf(And(
+				HaveField("Phase", observabilityv1alpha1.TargetPhaseFailed),
+				HaveField("LastError", ContainSubstring(`container named "vector"`)),
+			)))
+		})
+
 		It("Should calculate consistent injection hash", func() {
 			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
 				Spec: observabilityv1alpha1.VectorSidecarSpec{
@@ -421,29 +3658,226 @@ var _ = Describe("VectorSidecar Controller", func() {
 				Scheme: s,
 			}
 
-			hash1, err := reconciler.calculateInjectionHash(vectorSidecar)
+			hash1, err := reconciler.calculateInjectionHash(vectorSidecar, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(hash1).NotTo(BeEmpty())
 
 			// Calculate again to ensure consistency
-			hash2, err := reconciler.calculateInjectionHash(vectorSidecar)
+			hash2, err := reconciler.calculateInjectionHash(vectorSidecar, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(hash2).To(Equal(hash1))
 
 			// Change image and verify hash changes
 			vectorSidecar.Spec.Sidecar.Image = "timberio/vector:0.36.0"
-			hash3, err := reconciler.calculateInjectionHash(vectorSidecar)
+			hash3, err := reconciler.calculateInjectionHash(vectorSidecar, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(hash3).NotTo(Equal(hash1))
+			Expect(hash3).To(HavePrefix(injectionHashVersion + "-"))
+		})
+
+		It("Should hash everything rendered into the pod", func() {
+			vectorSidecar := &observabilityv1alpha1.VectorSidecar{
+				Spec: observabilityv1alpha1.VectorSidecarSpec{
+					Sidecar: observabilityv1alpha1.SidecarConfig{
+						Image: "timberio/vector:0.35.0",
+						Config: observabilityv1alpha1.VectorConfig{
+							ConfigMapRef: &observabilityv1alpha1.ConfigMapRef{Name: "vector-config"},
+						},
+					},
+				},
+			}
+			reconciler := &VectorSidecarReconciler{}
+
+			base, err := reconciler.calculateInjectionHash(vectorSidecar, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			// The defaulted pull policy renders the same container, so it keeps the hash
+			vectorSidecar.Spec.Sidecar.ImagePullPolicy = corev1.PullIfNotPresent
+			Expect(reconciler.calculateInjectionHash(vectorSidecar, "")).To(Equal(base))
+
+			changes := map[string]func(vs *observabilityv1alpha1.VectorSidecar){
+				"sidecar name": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Name = "log-shipper"
+				},
+				"image pull policy": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.ImagePullPolicy = corev1.PullAlways
+				},
+				"init containers": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.InitContainers = []corev1.Container{{Name: "setup", Image: "busybox:latest"}}
+				},
+				"configmap key": func(vs *observabilityv1alpha1.VectorSidecar) {
+					vs.Spec.Sidecar.Config.ConfigMapRef.Key = "vector.toml"
+				},
+			}
+			for change, mutate := range changes {
+				vs := vectorSidecar.DeepCopy()
+				mutate(vs)
+				hash, err := reconciler.calculateInjectionHash(vs, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hash).NotTo(Equal(base), "expected a %s change to roll the pods", change)
+			}
+
+			// So does the content behind the ConfigMap reference
+			Expect(reconciler.calculateInjectionHash(vectorSidecar, "0a1b2c3d")).NotTo(Equal(base))
 		})
 	})
 })
 
+// validVectorConfig is a minimal configuration that passes structural validation
+const validVectorConfig = `sources:
+  logs:
+    type: file
+    include: ["/var/log/*.log"]
+sinks:
+  out:
+    type: console
+    inputs: ["logs"]
+    encoding:
+      codec: json
+`
+
 // Helper functions
 func int32Ptr(i int32) *int32 {
 	return &i
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// newFakeClientBuilder returns a fake client builder with the workload indexes the manager registers
+func newFakeClientBuilder(s *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().WithScheme(s)
+	for _, kind := range supportedWorkloadKinds {
+		obj, _ := newWorkloadObject(kind)
+		for field, annotation := range ownerIndexFields {
+			builder = builder.WithIndex(obj, field, indexAnnotation(annotation))
+		}
+	}
+	return builder.WithIndex(&corev1.ConfigMap{}, clusterSidecarNameIndexField, indexAnnotation(AnnotationClusterVectorSidecarName))
+}
+
+func fakeDiscovery(gitVersion string) *discoveryfake.FakeDiscovery {
+	return &discoveryfake.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: gitVersion},
+	}
+}
+
+// flakyDiscovery returns err from ServerVersion until it is cleared
+type flakyDiscovery struct {
+	info *version.Info
+	err  error
+}
+
+func (d *flakyDiscovery) ServerVersion() (*version.Info, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.info, nil
+}
+
+// failingPatchClient rejects patches to the named object
+type failingPatchClient struct {
+	client.Client
+	name string
+	err  error
+}
+
+func (c *failingPatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if obj.GetName() == c.name {
+		return c.err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// conflictingStatusClient counts status patches and rejects the first conflicts of them
+type conflictingStatusClient struct {
+	client.Client
+	conflicts int
+	patches   int
+}
+
+func (c *conflictingStatusClient) Status() client.SubResourceWriter {
+	return &conflictingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type conflictingStatusWriter struct {
+	client.SubResourceWriter
+	client *conflictingStatusClient
+}
+
+func (w *conflictingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	w.client.patches++
+	if w.client.conflicts > 0 {
+		w.client.conflicts--
+		return apierrors.NewConflict(schema.GroupResource{Resource: "vectorsidecars"}, obj.GetName(), errors.New("object was modified"))
+	}
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// countingRateLimiter counts the writes that waited on it and fails them while err is set
+type countingRateLimiter struct {
+	waits int
+	err   error
+}
+
+func (l *countingRateLimiter) TryAccept() bool { return l.err == nil }
+func (l *countingRateLimiter) Accept()         {}
+func (l *countingRateLimiter) Stop()           {}
+func (l *countingRateLimiter) QPS() float32    { return 0 }
+
+func (l *countingRateLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return l.err
+}
+
+// listRecordingClient records the list requests it passes on
+type listRecordingClient struct {
+	client.Client
+	lists   []client.ObjectList
+	options []*client.ListOptions
+}
+
+func (c *listRecordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	options := &client.ListOptions{}
+	options.ApplyOptions(opts)
+	c.lists = append(c.lists, list)
+	c.options = append(c.options, options)
+	return c.Client.List(ctx, list, opts...)
+}
+
+// applyRecordingClient records the server-side apply requests it passes on
+type applyRecordingClient struct {
+	client.Client
+	applied       []*unstructured.Unstructured
+	fieldManagers []string
+}
+
+func (c *applyRecordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		options := &client.PatchOptions{}
+		options.ApplyOptions(opts)
+		if options.Force == nil || !*options.Force {
+			return errors.New("apply without forced ownership")
+		}
+		c.applied = append(c.applied, obj.(*unstructured.Unstructured).DeepCopy())
+		c.fieldManagers = append(c.fieldManagers, options.FieldManager)
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func admissionRequest(namespace string, rawPod []byte) admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			UID:       "test",
+			Namespace: namespace,
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: rawPod},
+		},
+	}
+}
+
 func findCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
 	for _, condition := range conditions {
 		if condition.Type == conditionType {
@@ -0,0 +1,400 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	observabilityv1alpha1 "github.com/amitde789696/vector-sidecar-operator/api/v1alpha1"
+)
+
+// supportedWorkloadKinds lists every workload kind the operator knows how to inject into
+var supportedWorkloadKinds = []observabilityv1alpha1.WorkloadKind{
+	observabilityv1alpha1.WorkloadKindDeployment,
+	observabilityv1alpha1.WorkloadKindStatefulSet,
+	observabilityv1alpha1.WorkloadKindDaemonSet,
+	observabilityv1alpha1.WorkloadKindReplicaSet,
+	observabilityv1alpha1.WorkloadKindCronJob,
+}
+
+// Field indexes over the annotations recording which resource injected a workload, so the
+// workloads of one VectorSidecar are found without listing the whole namespace. The cluster
+// index also covers the ConfigMaps a ClusterVectorSidecar copies into each namespace.
+const (
+	sidecarNameIndexField        = ".metadata.annotations.sidecar-name"
+	clusterSidecarNameIndexField = ".metadata.annotations.cluster-sidecar-name"
+)
+
+// ownerIndexFields maps each owner annotation index to the annotation it is computed from
+var ownerIndexFields = map[string]string{
+	sidecarNameIndexField:        AnnotationVectorSidecarName,
+	clusterSidecarNameIndexField: AnnotationClusterVectorSidecarName,
+}
+
+// workload wraps an object that carries a pod template so the selector, hash
+// and annotation logic can be shared across workload kinds
+type workload struct {
+	client.Object
+
+	// Kind is the workload kind of the wrapped object
+	Kind observabilityv1alpha1.WorkloadKind
+
+	// Template points into the wrapped object's pod template
+	Template *corev1.PodTemplateSpec
+}
+
+// newWorkload wraps a supported workload object
+func newWorkload(obj client.Object) (*workload, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload{Object: o, Kind: observabilityv1alpha1.WorkloadKindDeployment, Template: &o.Spec.Template}, nil
+	case *appsv1.StatefulSet:
+		return &workload{Object: o, Kind: observabilityv1alpha1.WorkloadKindStatefulSet, Template: &o.Spec.Template}, nil
+	case *appsv1.DaemonSet:
+		return &workload{Object: o, Kind: observabilityv1alpha1.WorkloadKindDaemonSet, Template: &o.Spec.Template}, nil
+	case *appsv1.ReplicaSet:
+		return &workload{Object: o, Kind: observabilityv1alpha1.WorkloadKindReplicaSet, Template: &o.Spec.Template}, nil
+	case *batchv1.CronJob:
+		return &workload{Object: o, Kind: observabilityv1alpha1.WorkloadKindCronJob, Template: &o.Spec.JobTemplate.Spec.Template}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload type %T", obj)
+	}
+}
+
+// DeepCopy returns a workload wrapping a deep copy of the underlying object
+func (w *workload) DeepCopy() *workload {
+	copied, _ := newWorkload(w.Object.DeepCopyObject().(client.Object))
+	return copied
+}
+
+// String returns the workload as Kind/name for logs and events
+func (w *workload) String() string {
+	return fmt.Sprintf("%s/%s", w.Kind, w.GetName())
+}
+
+// isBatch reports whether the workload runs pods to completion
+func (w *workload) isBatch() bool {
+	return w.Kind == observabilityv1alpha1.WorkloadKindCronJob
+}
+
+// podSpecPath returns the JSON path of the pod spec within the workload object
+func (w *workload) podSpecPath() []string {
+	if w.Kind == observabilityv1alpha1.WorkloadKindCronJob {
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+	return []string{"spec", "template", "spec"}
+}
+
+// rolloutComplete reports whether the workload's controller finished rolling out its current pod
+// template: every replica updated and available, or for a Deployment the Available condition true.
+// CronJobs only affect future Jobs, so they are complete as soon as they are written.
+func (w *workload) rolloutComplete() bool {
+	switch o := w.Object.(type) {
+	case *appsv1.Deployment:
+		replicas := replicaCount(o.Spec.Replicas)
+		if o.Status.ObservedGeneration < o.Generation || o.Status.UpdatedReplicas < replicas ||
+			o.Status.AvailableReplicas < replicas || o.Status.Replicas > replicas {
+			return false
+		}
+		if replicas == 0 {
+			return true
+		}
+		for _, condition := range o.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable {
+				return condition.Status == corev1.ConditionTrue
+			}
+		}
+		return false
+	case *appsv1.StatefulSet:
+		replicas := replicaCount(o.Spec.Replicas)
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.UpdatedReplicas >= replicas &&
+			o.Status.AvailableReplicas >= replicas
+	case *appsv1.DaemonSet:
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedNumberScheduled >= o.Status.DesiredNumberScheduled &&
+			o.Status.NumberAvailable >= o.Status.DesiredNumberScheduled
+	case *appsv1.ReplicaSet:
+		// A ReplicaSet does not replace running pods when its template changes
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.AvailableReplicas >= replicaCount(o.Spec.Replicas)
+	default:
+		return true
+	}
+}
+
+// restartedPods returns the number of running pods a pod template change replaces. ReplicaSets
+// and CronJobs only apply the template to the pods they create later.
+func (w *workload) restartedPods() int32 {
+	switch o := w.Object.(type) {
+	case *appsv1.Deployment:
+		return replicaCount(o.Spec.Replicas)
+	case *appsv1.StatefulSet:
+		return replicaCount(o.Spec.Replicas)
+	case *appsv1.DaemonSet:
+		return o.Status.DesiredNumberScheduled
+	default:
+		return 0
+	}
+}
+
+// podSelector returns the selector of the pods the workload runs. Jobs created by a CronJob
+// carry the labels of its job template.
+func (w *workload) podSelector() (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+	switch o := w.Object.(type) {
+	case *appsv1.Deployment:
+		selector = o.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = o.Spec.Selector
+	case *appsv1.DaemonSet:
+		selector = o.Spec.Selector
+	case *appsv1.ReplicaSet:
+		selector = o.Spec.Selector
+	default:
+		return labels.SelectorFromSet(w.Template.Labels), nil
+	}
+	if selector == nil {
+		return labels.Nothing(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// replicaCount returns the desired replicas, defaulting to one like the API server
+func replicaCount(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// groupVersionKind returns the API group, version and kind of the workload object
+func (w *workload) groupVersionKind() schema.GroupVersionKind {
+	return workloadGroupVersionKind(w.Kind)
+}
+
+// workloadGroupVersionKind returns the API group, version and kind of a workload kind
+func workloadGroupVersionKind(kind observabilityv1alpha1.WorkloadKind) schema.GroupVersionKind {
+	if kind == observabilityv1alpha1.WorkloadKindCronJob {
+		return batchv1.SchemeGroupVersion.WithKind(string(kind))
+	}
+	return appsv1.SchemeGroupVersion.WithKind(string(kind))
+}
+
+// newWorkloadList returns an empty list object for the given workload kind
+func newWorkloadList(kind observabilityv1alpha1.WorkloadKind) (client.ObjectList, error) {
+	switch kind {
+	case observabilityv1alpha1.WorkloadKindDeployment:
+		return &appsv1.DeploymentList{}, nil
+	case observabilityv1alpha1.WorkloadKindStatefulSet:
+		return &appsv1.StatefulSetList{}, nil
+	case observabilityv1alpha1.WorkloadKindDaemonSet:
+		return &appsv1.DaemonSetList{}, nil
+	case observabilityv1alpha1.WorkloadKindReplicaSet:
+		return &appsv1.ReplicaSetList{}, nil
+	case observabilityv1alpha1.WorkloadKindCronJob:
+		return &batchv1.CronJobList{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+}
+
+// listWorkloads lists workloads of the given kind, skipping ReplicaSets and Jobs managed by a controller
+func (r *VectorSidecarReconciler) listWorkloads(ctx context.Context, kind observabilityv1alpha1.WorkloadKind, opts ...client.ListOption) ([]*workload, error) {
+	list, err := newWorkloadList(kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list %s workloads: %w", kind, err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make([]*workload, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		// ReplicaSets owned by a Deployment are injected through their owner
+		if kind == observabilityv1alpha1.WorkloadKindReplicaSet && metav1.GetControllerOf(obj) != nil {
+			continue
+		}
+		wl, err := newWorkload(obj)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, wl)
+	}
+	return workloads, nil
+}
+
+// listMatchingWorkloads lists the workloads of the given kind in the namespace whose labels match
+// the selector. The selector is evaluated by the cache, so non-matching workloads are never copied.
+func (r *VectorSidecarReconciler) listMatchingWorkloads(ctx context.Context, kind observabilityv1alpha1.WorkloadKind,
+	namespace string, selector labels.Selector) ([]*workload, error) {
+	return r.listWorkloads(ctx, kind, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+}
+
+// ownerIndexField returns the field index over the annotation recording that the resource
+// injected a workload
+func ownerIndexField(vectorSidecar *observabilityv1alpha1.VectorSidecar) string {
+	if isClusterScoped(vectorSidecar) {
+		return clusterSidecarNameIndexField
+	}
+	return sidecarNameIndexField
+}
+
+// indexAnnotation returns an indexer extracting the value of the annotation
+func indexAnnotation(key string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		if value := obj.GetAnnotations()[key]; value != "" {
+			return []string{value}
+		}
+		return nil
+	}
+}
+
+// targetKinds returns the workload kinds a VectorSidecar selects, defaulting to Deployments
+func targetKinds(vectorSidecar *observabilityv1alpha1.VectorSidecar) []observabilityv1alpha1.WorkloadKind {
+	if len(vectorSidecar.Spec.TargetKinds) == 0 {
+		return []observabilityv1alpha1.WorkloadKind{observabilityv1alpha1.WorkloadKindDeployment}
+	}
+	return vectorSidecar.Spec.TargetKinds
+}
+
+// newWorkloadObject returns an empty object of the given workload kind
+func newWorkloadObject(kind observabilityv1alpha1.WorkloadKind) (client.Object, error) {
+	switch kind {
+	case observabilityv1alpha1.WorkloadKindDeployment:
+		return &appsv1.Deployment{}, nil
+	case observabilityv1alpha1.WorkloadKindStatefulSet:
+		return &appsv1.StatefulSet{}, nil
+	case observabilityv1alpha1.WorkloadKindDaemonSet:
+		return &appsv1.DaemonSet{}, nil
+	case observabilityv1alpha1.WorkloadKindReplicaSet:
+		return &appsv1.ReplicaSet{}, nil
+	case observabilityv1alpha1.WorkloadKindCronJob:
+		return &batchv1.CronJob{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+}
+
+// vectorSidecarsForWorkload maps a workload event to every VectorSidecar in the namespace
+// whose selector matches it, plus the VectorSidecar recorded in its annotations, so new,
+// relabelled and drifted workloads are reconciled without waiting for the resync
+func (r *VectorSidecarReconciler) vectorSidecarsForWorkload(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	wl, err := newWorkload(obj)
+	if err != nil {
+		return nil
+	}
+	// ReplicaSets owned by a Deployment are injected through their owner
+	if wl.Kind == observabilityv1alpha1.WorkloadKindReplicaSet && metav1.GetControllerOf(obj) != nil {
+		return nil
+	}
+
+	vectorSidecars := &observabilityv1alpha1.VectorSidecarList{}
+	if err := r.List(ctx, vectorSidecars, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VectorSidecars for workload", "workload", wl.String())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range vectorSidecars.Items {
+		vectorSidecar := &vectorSidecars.Items[i]
+		if wl.GetAnnotations()[AnnotationVectorSidecarName] == vectorSidecar.Name || selectsWorkload(vectorSidecar, wl) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: vectorSidecar.Name, Namespace: vectorSidecar.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// selectsWorkload reports whether the workload is of a target kind and matches the selector.
+// With the pod injection strategy the selector applies to the pod template labels.
+func selectsWorkload(vectorSidecar *observabilityv1alpha1.VectorSidecar, wl *workload) bool {
+	targeted := false
+	for _, kind := range targetKinds(vectorSidecar) {
+		if kind == wl.Kind {
+			targeted = true
+			break
+		}
+	}
+	if !targeted {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&vectorSidecar.Spec.Selector)
+	if err != nil {
+		return false
+	}
+
+	workloadLabels := wl.GetLabels()
+	if vectorSidecar.Spec.InjectionStrategy == observabilityv1alpha1.InjectionStrategyPod {
+		workloadLabels = wl.Template.Labels
+	}
+	return selector.Matches(labels.Set(workloadLabels))
+}
+
+// createPodTemplatePatch computes the strategic merge patch from original to modified and marks
+// the nativeSidecar init container, if any, in the pod spec found at podSpecPath
+func createPodTemplatePatch(original, modified runtime.Object, podSpecPath []string, nativeSidecar string) ([]byte, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedJSON, err := json.Marshal(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := strategicpatch.CreateTwoWayMergePatch(originalJSON, modifiedJSON, original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch: %w", err)
+	}
+
+	if nativeSidecar != "" {
+		patch, err = setNativeSidecarRestartPolicy(patch, podSpecPath, nativeSidecar)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return patch, nil
+}
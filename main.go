@@ -18,7 +18,9 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -26,7 +28,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -54,11 +58,34 @@ func main() {
 	var probeAddr string
 	var disableMetrics bool
 	var disableHealthProbes bool
+	var enableWebhooks bool
+	var orphanGCInterval time.Duration
+	var orphanPolicy string
+	var controllerOptions controllers.ControllerOptions
+	var workloadWriteQPS float64
+	var workloadWriteBurst int
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&disableMetrics, "disable-metrics", false, "Disable metrics server to avoid port conflicts in dev environments")
 	flag.BoolVar(&disableHealthProbes, "disable-health-probes", false, "Disable health probe endpoints to avoid port conflicts in dev environments")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks on port 9443. Requires serving certificates in the webhook server cert directory.")
+	flag.DurationVar(&orphanGCInterval, "orphan-gc-interval", 10*time.Minute,
+		"How often workloads injected by a deleted VectorSidecar are collected. 0 disables the sweep.")
+	flag.StringVar(&orphanPolicy, "orphan-policy", string(controllers.OrphanPolicyStrip),
+		"What to do with a workload injected by a deleted VectorSidecar: strip removes the sidecar, "+
+			"adopt hands it to the VectorSidecar now selecting it and strips it when none does.")
+	flag.IntVar(&controllerOptions.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"How many VectorSidecars, and separately ClusterVectorSidecars, are reconciled in parallel.")
+	flag.DurationVar(&controllerOptions.BackoffBaseDelay, "backoff-base-delay", 5*time.Millisecond,
+		"The delay before retrying a failed reconcile, doubled on every consecutive failure.")
+	flag.DurationVar(&controllerOptions.BackoffMaxDelay, "backoff-max-delay", 1000*time.Second,
+		"The longest delay between retries of a failing reconcile.")
+	flag.Float64Var(&workloadWriteQPS, "workload-write-qps", 0,
+		"The maximum sustained rate of writes to workloads across all VectorSidecars. 0 disables the limit.")
+	flag.IntVar(&workloadWriteBurst, "workload-write-burst", 10,
+		"The number of workload writes allowed in a burst above workload-write-qps.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -70,6 +97,27 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if policy := controllers.OrphanPolicy(orphanPolicy); policy != controllers.OrphanPolicyStrip && policy != controllers.OrphanPolicyAdopt {
+		setupLog.Error(fmt.Errorf("unknown orphan policy %q", orphanPolicy), "invalid flag", "flag", "orphan-policy")
+		os.Exit(1)
+	}
+
+	if controllerOptions.MaxConcurrentReconciles < 1 {
+		setupLog.Error(fmt.Errorf("must be at least 1, got %d", controllerOptions.MaxConcurrentReconciles),
+			"invalid flag", "flag", "max-concurrent-reconciles")
+		os.Exit(1)
+	}
+	if controllerOptions.BackoffBaseDelay <= 0 || controllerOptions.BackoffMaxDelay < controllerOptions.BackoffBaseDelay {
+		setupLog.Error(fmt.Errorf("need 0 < backoff-base-delay (%s) <= backoff-max-delay (%s)",
+			controllerOptions.BackoffBaseDelay, controllerOptions.BackoffMaxDelay), "invalid flag", "flag", "backoff-base-delay")
+		os.Exit(1)
+	}
+	if workloadWriteQPS < 0 || (workloadWriteQPS > 0 && workloadWriteBurst < 1) {
+		setupLog.Error(fmt.Errorf("need a non-negative qps and a positive burst, got %v and %d", workloadWriteQPS, workloadWriteBurst),
+			"invalid flag", "flag", "workload-write-qps")
+		os.Exit(1)
+	}
+
 	// Disable metrics and health probes if requested
 	if disableMetrics {
 		metricsAddr = "0"
@@ -104,14 +152,63 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.VectorSidecarReconciler{
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+
+	vectorSidecarReconciler := &controllers.VectorSidecarReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("vectorsidecar-controller"),
+		Discovery: discoveryClient,
+		APIReader: mgr.GetAPIReader(),
+		Options:   controllerOptions,
+	}
+	if workloadWriteQPS > 0 {
+		vectorSidecarReconciler.WorkloadWriteLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(workloadWriteQPS), workloadWriteBurst)
+	}
+	if err = vectorSidecarReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VectorSidecar")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterVectorSidecarReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("vectorsidecar-controller"),
+		Recorder: mgr.GetEventRecorderFor("clustervectorsidecar-controller"),
+		Injector: vectorSidecarReconciler,
+		Options:  controllerOptions,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "VectorSidecar")
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterVectorSidecar")
 		os.Exit(1)
 	}
+	if orphanGCInterval > 0 {
+		if err = mgr.Add(&controllers.OrphanCollector{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("vectorsidecar-orphan-collector"),
+			Injector: vectorSidecarReconciler,
+			Interval: orphanGCInterval,
+			Policy:   controllers.OrphanPolicy(orphanPolicy),
+		}); err != nil {
+			setupLog.Error(err, "unable to add runnable", "runnable", "OrphanCollector")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		if err = vectorSidecarReconciler.SetupPodWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+		if err = (&observabilityv1alpha1.VectorSidecar{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VectorSidecar")
+			os.Exit(1)
+		}
+		if err = (&observabilityv1alpha1.ClusterVectorSidecar{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterVectorSidecar")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {